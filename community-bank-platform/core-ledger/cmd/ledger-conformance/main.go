@@ -0,0 +1,92 @@
+// Command ledger-conformance drives internal/store/conformance's vector
+// corpus against a real Postgres instance and reports pass/fail per vector.
+//
+// The corpus directory also carries a manifest.json pointer file (sha256 per
+// vector) so an external project can fetch the identical files and replay
+// them with its own implementation. -record rewrites a vector's expect
+// block in place; re-run `sha256sum *.json` over the directory and update
+// manifest.json by hand afterward.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store/conformance"
+)
+
+func main() {
+	var (
+		dir    = flag.String("dir", "internal/store/conformance/testdata/vectors", "directory of *.json test vectors, plus a manifest.json pointer file")
+		record = flag.Bool("record", false, "overwrite each vector's expect block with the observed result instead of asserting")
+	)
+	flag.Parse()
+
+	if conformance.Skip() {
+		log.Println("SKIP_CONFORMANCE=1: skipping")
+		return
+	}
+
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		log.Fatal("LEDGER_DB_DSN is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("connect: %v", err)
+	}
+	defer pool.Close()
+
+	vectors, err := conformance.LoadDir(*dir)
+	if err != nil {
+		log.Fatalf("load vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		log.Fatalf("no vectors found under %s", *dir)
+	}
+
+	failed := 0
+	for _, v := range vectors {
+		res, err := conformance.Run(ctx, pool, v)
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL %s: %v\n", v.Name, err)
+			continue
+		}
+		if *record {
+			v.Expect.EventLogLength = res.EventCount
+			v.Expect.ChainHeadHash = res.HeadHash
+			v.Expect.EventPayloadHashes = res.EventPayloadHashes
+			v.Expect.DBRunFingerprint = res.DBRunFingerprint
+			path := fmt.Sprintf("%s/%s.json", *dir, v.Name)
+			if err := conformance.WriteObserved(path, v); err != nil {
+				log.Fatalf("record %s: %v", v.Name, err)
+			}
+			fmt.Printf("RECORDED %s (%d steps, head=%s)\n", v.Name, res.StepsRun, res.HeadHash)
+			continue
+		}
+		if !res.OK() {
+			failed++
+			fmt.Printf("FAIL %s (%d steps):\n", v.Name, res.StepsRun)
+			for _, f := range res.Failures {
+				fmt.Printf("  - %s\n", f)
+			}
+			continue
+		}
+		fmt.Printf("OK   %s (%d steps, head=%s)\n", v.Name, res.StepsRun, res.HeadHash)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,102 @@
+// Command ledger-migrate is the operator-facing front end for
+// internal/store's versioned migrations: it lets a human (or a deploy step)
+// apply, roll back, inspect, and recover the schema_migrations bookkeeping
+// without reaching for psql.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: ledger-migrate <command>
+
+commands:
+  up             apply every pending migration
+  down N         roll back to version N
+  status         list every migration and whether it's applied
+  force VERSION  stamp schema_migrations to VERSION without running SQL`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		log.Fatal("LEDGER_DB_DSN is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		log.Fatalf("connect: %v", err)
+	}
+	defer pool.Close()
+
+	switch os.Args[1] {
+	case "up":
+		if err := store.Migrate(ctx, pool); err != nil {
+			log.Fatalf("up: %v", err)
+		}
+		fmt.Println("OK: migrated to latest")
+
+	case "down":
+		if len(os.Args) != 3 {
+			usage()
+			os.Exit(2)
+		}
+		version, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("down: bad version %q: %v", os.Args[2], err)
+		}
+		if err := store.MigrateTo(ctx, pool, version); err != nil {
+			log.Fatalf("down: %v", err)
+		}
+		fmt.Printf("OK: migrated to %d\n", version)
+
+	case "status":
+		statuses, err := store.MigrateStatus(ctx, pool)
+		if err != nil {
+			log.Fatalf("status: %v", err)
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("%04d_%s  applied %s\n", s.Version, s.Name, s.AppliedAt.Format(time.RFC3339))
+			} else {
+				fmt.Printf("%04d_%s  pending\n", s.Version, s.Name)
+			}
+		}
+
+	case "force":
+		if len(os.Args) != 3 {
+			usage()
+			os.Exit(2)
+		}
+		version, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("force: bad version %q: %v", os.Args[2], err)
+		}
+		if err := store.ForceVersion(ctx, pool, version); err != nil {
+			log.Fatalf("force: %v", err)
+		}
+		fmt.Printf("OK: schema_migrations forced to %d\n", version)
+
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
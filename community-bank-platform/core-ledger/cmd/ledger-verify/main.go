@@ -0,0 +1,85 @@
+// Command ledger-verify is the stateless auditor side of pkg/eventproof: it
+// takes a proof blob (as served by GET /v1/events/{seq}/proof) and a trusted
+// root (from GET /v1/checkpoints/latest, or an externally anchored record)
+// and confirms the proof actually roots there -- no DB access required.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"core-ledger/pkg/eventproof"
+)
+
+func main() {
+	var (
+		proofPath = flag.String("proof", "", "path to a JSON-encoded eventproof.Proof")
+		root      = flag.String("root", "", "trusted checkpoint root (hex); defaults to the proof's own root if omitted")
+		pubKeyHex = flag.String("pubkey", "", "hex Ed25519 public key; if set, -sig must also be set")
+		sigHex    = flag.String("sig", "", "hex Ed25519 signature over the checkpoint root")
+		seqLo     = flag.Uint64("seq-lo", 0, "checkpoint seq_lo, required with -pubkey")
+		seqHi     = flag.Uint64("seq-hi", 0, "checkpoint seq_hi, required with -pubkey")
+	)
+	flag.Parse()
+
+	if *proofPath == "" {
+		fmt.Fprintln(os.Stderr, "missing -proof")
+		os.Exit(2)
+	}
+
+	raw, err := os.ReadFile(*proofPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "read proof:", err)
+		os.Exit(2)
+	}
+
+	var p eventproof.Proof
+	if err := json.Unmarshal(raw, &p); err != nil {
+		fmt.Fprintln(os.Stderr, "parse proof:", err)
+		os.Exit(2)
+	}
+
+	trustedRoot := *root
+	if trustedRoot == "" {
+		trustedRoot = p.Root
+	}
+
+	ok, err := eventproof.Verify(p, trustedRoot)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verify:", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, "FAIL: proof does not root in", trustedRoot)
+		os.Exit(1)
+	}
+
+	if *pubKeyHex != "" {
+		if *sigHex == "" || *seqLo == 0 || *seqHi == 0 {
+			fmt.Fprintln(os.Stderr, "missing -sig/-seq-lo/-seq-hi")
+			os.Exit(2)
+		}
+		pub, err := hex.DecodeString(*pubKeyHex)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "bad -pubkey:", err)
+			os.Exit(2)
+		}
+		sig, err := hex.DecodeString(*sigHex)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "bad -sig:", err)
+			os.Exit(2)
+		}
+		if !eventproof.VerifyRootSignature(ed25519.PublicKey(pub), sig, trustedRoot, *seqLo, *seqHi) {
+			fmt.Fprintln(os.Stderr, "FAIL: checkpoint signature does not verify")
+			os.Exit(1)
+		}
+		fmt.Println("OK: proof verified, checkpoint signature verified")
+		return
+	}
+
+	fmt.Printf("OK: event seq=%d included under root=%s\n", p.Seq, trustedRoot)
+}
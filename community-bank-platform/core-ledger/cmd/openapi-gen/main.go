@@ -0,0 +1,31 @@
+// Command openapi-gen writes the generated OpenAPI document for
+// httpapi's HTTP surface to disk. Run via `make docsgen`; its output is
+// committed at build/openapi/ledger.json and checked for drift by
+// internal/openapi's TestGenerate_MatchesCommittedSpec.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"core-ledger/internal/openapi"
+)
+
+func main() {
+	out := flag.String("out", "build/openapi/ledger.json", "output path for the generated spec")
+	flag.Parse()
+
+	b, err := json.MarshalIndent(openapi.Generate(), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "marshal:", err)
+		os.Exit(1)
+	}
+	b = append(b, '\n')
+
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "write:", err)
+		os.Exit(1)
+	}
+}
@@ -1,22 +1,41 @@
-package proofverify
+// Command proof-verify is a standalone auditor tool for a CSV export of
+// event_log_proof_export_v: it walks the hash chain end to end and confirms
+// every row's prev_hash_hex matches its predecessor's hash_hex. With
+// -anchor and -attestations it also verifies signed head attestations
+// exported from event_log_attestation_export_v (see store.SignHead), so the
+// final head doesn't have to be trusted out of band via a bare -head value.
 package main
 
 import (
 	"bufio"
+	"crypto/ed25519"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/csv"
 	"encoding/hex"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
 type row struct {
-	Seq         string
-	PrevHex     string
-	HashHex     string
+	Seq     int64
+	PrevHex string
+	HashHex string
+}
+
+// attestation mirrors one row of event_log_attestation_export_v.
+type attestation struct {
+	Seq              int64
+	HeadHashHex      string
+	DBRunFingerprint string
+	KeyID            string
+	Alg              string
+	SignatureHex     string
 }
 
 func sha256Hex(s string) string {
@@ -24,10 +43,87 @@ func sha256Hex(s string) string {
 	return hex.EncodeToString(sum[:])
 }
 
+// attestationSigningMessage mirrors internal/store's unexported function of
+// the same name; proof-verify has no DB dependency and can't import it.
+func attestationSigningMessage(alg string, seq int64, headHash, dbRunFingerprint string) []byte {
+	return []byte(fmt.Sprintf("ledger-attestation:v1|%s|%d|%s|%s", alg, seq, headHash, dbRunFingerprint))
+}
+
+func loadAnchorPubKey(path string) (ed25519.PublicKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an Ed25519 public key", path)
+	}
+	return pub, nil
+}
+
+func loadAttestations(path string) ([]attestation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(bufio.NewReader(f))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.TrimSpace(h)] = i
+	}
+	for _, need := range []string{"seq", "head_hash_hex", "db_run_fingerprint", "key_id", "alg", "signature_hex"} {
+		if _, ok := col[need]; !ok {
+			return nil, fmt.Errorf("missing column: %s", need)
+		}
+	}
+
+	var out []attestation
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv read: %w", err)
+		}
+		seq, err := strconv.ParseInt(strings.TrimSpace(rec[col["seq"]]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad seq %q: %w", rec[col["seq"]], err)
+		}
+		out = append(out, attestation{
+			Seq:              seq,
+			HeadHashHex:      strings.ToLower(strings.TrimSpace(rec[col["head_hash_hex"]])),
+			DBRunFingerprint: strings.ToLower(strings.TrimSpace(rec[col["db_run_fingerprint"]])),
+			KeyID:            strings.TrimSpace(rec[col["key_id"]]),
+			Alg:              strings.ToLower(strings.TrimSpace(rec[col["alg"]])),
+			SignatureHex:     strings.ToLower(strings.TrimSpace(rec[col["signature_hex"]])),
+		})
+	}
+	return out, nil
+}
+
 func main() {
 	var (
-		inPath   = flag.String("in", "", "CSV exported from event_log_proof_export_v")
-		headHash = flag.String("head", "", "expected head hash hex (db_run_fingerprint)")
+		inPath     = flag.String("in", "", "CSV exported from event_log_proof_export_v")
+		headHash   = flag.String("head", "", "expected head hash hex, trusted out of band; ignored if -anchor is set")
+		anchorPath = flag.String("anchor", "", "PEM-encoded Ed25519 public key; verifies -attestations instead of trusting -head")
+		attestPath = flag.String("attestations", "", "CSV exported from event_log_attestation_export_v, required with -anchor")
 	)
 	flag.Parse()
 
@@ -35,8 +131,12 @@ func main() {
 		fmt.Fprintln(os.Stderr, "missing -in")
 		os.Exit(2)
 	}
-	if *headHash == "" {
-		fmt.Fprintln(os.Stderr, "missing -head")
+	if *anchorPath == "" && *headHash == "" {
+		fmt.Fprintln(os.Stderr, "missing -head (or -anchor together with -attestations)")
+		os.Exit(2)
+	}
+	if *anchorPath != "" && *attestPath == "" {
+		fmt.Fprintln(os.Stderr, "-anchor requires -attestations")
 		os.Exit(2)
 	}
 
@@ -72,7 +172,9 @@ func main() {
 		lineNo      = 1
 		prevHashHex string
 		lastHashHex string
+		lastSeq     int64
 		rows        int
+		hashBySeq   = map[int64]string{}
 	)
 
 	for {
@@ -86,8 +188,14 @@ func main() {
 			os.Exit(2)
 		}
 
+		seq, err := strconv.ParseInt(strings.TrimSpace(rec[col["seq"]]), 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: invalid seq: %v\n", lineNo, err)
+			os.Exit(1)
+		}
+
 		cur := row{
-			Seq:     rec[col["seq"]],
+			Seq:     seq,
 			PrevHex: strings.ToLower(strings.TrimSpace(rec[col["prev_hash_hex"]])),
 			HashHex: strings.ToLower(strings.TrimSpace(rec[col["hash_hex"]])),
 		}
@@ -105,7 +213,7 @@ func main() {
 		if rows > 0 {
 			// chain check: prev_hash(i) == hash(i-1)
 			if cur.PrevHex != prevHashHex {
-				fmt.Fprintf(os.Stderr, "FAIL: prev_hash mismatch at seq=%s line=%d\nexpected=%s\ngot=%s\n",
+				fmt.Fprintf(os.Stderr, "FAIL: prev_hash mismatch at seq=%d line=%d\nexpected=%s\ngot=%s\n",
 					cur.Seq, lineNo, prevHashHex, cur.PrevHex)
 				os.Exit(1)
 			}
@@ -113,6 +221,8 @@ func main() {
 
 		prevHashHex = cur.HashHex
 		lastHashHex = cur.HashHex
+		lastSeq = cur.Seq
+		hashBySeq[cur.Seq] = cur.HashHex
 		rows++
 	}
 
@@ -121,11 +231,66 @@ func main() {
 		os.Exit(1)
 	}
 
-	if strings.ToLower(strings.TrimSpace(*headHash)) != lastHashHex {
-		fmt.Fprintf(os.Stderr, "FAIL: head hash mismatch\nexpected=%s\ngot=%s\n", *headHash, lastHashHex)
+	if *anchorPath == "" {
+		if strings.ToLower(strings.TrimSpace(*headHash)) != lastHashHex {
+			fmt.Fprintf(os.Stderr, "FAIL: head hash mismatch\nexpected=%s\ngot=%s\n", *headHash, lastHashHex)
+			os.Exit(1)
+		}
+		fmt.Printf("OK: chain verified (%d rows). head=%s\n", rows, lastHashHex)
+		return
+	}
+
+	pub, err := loadAnchorPubKey(*anchorPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "anchor:", err)
+		os.Exit(2)
+	}
+	attestations, err := loadAttestations(*attestPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "attestations:", err)
+		os.Exit(2)
+	}
+	if len(attestations) == 0 {
+		fmt.Fprintln(os.Stderr, "FAIL: no attestations")
+		os.Exit(1)
+	}
+
+	var last *attestation
+	for i := range attestations {
+		a := &attestations[i]
+
+		wantHash, ok := hashBySeq[a.Seq]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "FAIL: attestation seq=%d is not present in -in export\n", a.Seq)
+			os.Exit(1)
+		}
+		if a.HeadHashHex != wantHash {
+			fmt.Fprintf(os.Stderr, "FAIL: attestation seq=%d claims head=%s but export has hash_hex=%s\n", a.Seq, a.HeadHashHex, wantHash)
+			os.Exit(1)
+		}
+
+		sig, err := hex.DecodeString(a.SignatureHex)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FAIL: attestation seq=%d: invalid signature_hex: %v\n", a.Seq, err)
+			os.Exit(1)
+		}
+		msg := attestationSigningMessage(a.Alg, a.Seq, a.HeadHashHex, a.DBRunFingerprint)
+		if !ed25519.Verify(pub, msg, sig) {
+			fmt.Fprintf(os.Stderr, "FAIL: attestation seq=%d: signature does not verify\n", a.Seq)
+			os.Exit(1)
+		}
+
+		if last == nil || a.Seq > last.Seq {
+			last = a
+		}
+	}
+
+	if last.Seq != lastSeq {
+		fmt.Fprintf(os.Stderr, "FAIL: newest attestation covers seq=%d, but export's last row is seq=%d\n", last.Seq, lastSeq)
 		os.Exit(1)
 	}
 
-	fmt.Printf("OK: chain verified (%d rows). head=%s\n", rows, lastHashHex)
-	_ = sha256Hex // keep helper for future “strong” mode
+	fmt.Printf("OK: chain verified (%d rows), %d attestation(s) verified, head=%s anchored by key_id=%s\n",
+		rows, len(attestations), lastHashHex, last.KeyID)
+	_ = sha256Hex // keep helper for future "strong" mode
 }
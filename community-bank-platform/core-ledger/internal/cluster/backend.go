@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/store"
+)
+
+// Backend adapts a Node to store.Backend: mutating calls are proposed
+// through Raft (so they fail with ErrNotLeader on a follower, as a plain
+// HTTP handler wired against this would surface through RequireLeader
+// before ever reaching here), while reads are served straight off the
+// node's local, already-replicated state.
+type Backend struct {
+	node *Node
+}
+
+// NewBackend returns a store.Backend that routes writes through node's
+// Raft log. Pair it with RequireLeader on the mutating HTTP routes so a
+// follower never even calls in here for a write.
+func NewBackend(node *Node) *Backend { return &Backend{node: node} }
+
+var _ store.Backend = (*Backend)(nil)
+
+func (b *Backend) CreateAccount(ctx context.Context, label, currency, correlationID string) (uuid.UUID, error) {
+	res, err := b.node.Propose(ctx, LogEntry{
+		Op:            OpCreateAccount,
+		Label:         label,
+		Currency:      currency,
+		CorrelationID: correlationID,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return res.AccountID, nil
+}
+
+func (b *Backend) PostTransfer(
+	ctx context.Context,
+	fromAcc, toAcc uuid.UUID,
+	amountCents int64,
+	currency, externalRef, idemKey, correlationID string,
+	attachments ...store.Attachment,
+) (uuid.UUID, error) {
+	res, err := b.node.Propose(ctx, LogEntry{
+		Op:             OpPostTransfer,
+		FromAccountID:  fromAcc,
+		ToAccountID:    toAcc,
+		AmountCents:    amountCents,
+		Currency:       currency,
+		ExternalRef:    externalRef,
+		IdempotencyKey: idemKey,
+		CorrelationID:  correlationID,
+		Attachments:    attachments,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return res.TxID, nil
+}
+
+func (b *Backend) Balance(ctx context.Context, accountID uuid.UUID) (string, int64, error) {
+	return b.node.Backend().Balance(ctx, accountID)
+}
+
+func (b *Backend) VerifyEventChain(ctx context.Context) (bool, error) {
+	return b.node.Backend().VerifyEventChain(ctx)
+}
+
+func (b *Backend) ChainHead(ctx context.Context) (int64, string, error) {
+	return b.node.Backend().ChainHead(ctx)
+}
@@ -0,0 +1,141 @@
+package cluster
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cosignGossipTimeout bounds the whole GossipCosign pass (all peers
+// dialed in parallel), independent of any deadline the caller's ctx may
+// or may not carry -- a commit must not hang on an unreachable peer.
+const cosignGossipTimeout = 5 * time.Second
+
+// cosignMsg is the domain-separated message every replica signs: the real
+// ledger chain head (seq, hash_hex) as returned by store.Backend.ChainHead,
+// the same content-addressed value an external auditor checks via
+// cmd/proof-verify -- not FSM.ChainHead's Raft-internal replication hash,
+// which only attests log ordering within this cluster and means nothing
+// outside it.
+func cosignMsg(seq int64, headHex string) []byte {
+	return []byte(fmt.Sprintf("core-ledger/cluster/chain-head-v1|%d|%s", seq, headHex))
+}
+
+// cosignBook holds, per chain-head seq, the set of peer signatures
+// collected over that seq's hash_hex. It is process-local and rebuilt on
+// restart -- acceptable for a quorum-attestation scheme where any live
+// majority can re-sign, but it means a co-signature set does not outlive
+// the node that collected it. A production deployment that needs durable
+// attestations should persist these the way BuildCheckpoint persists
+// event_checkpoint.
+type cosignBook struct {
+	mu   sync.Mutex
+	sigs map[int64]map[string][]byte // seq -> nodeID -> signature
+}
+
+func newCosignBook() *cosignBook {
+	return &cosignBook{sigs: map[int64]map[string][]byte{}}
+}
+
+func (b *cosignBook) add(seq int64, nodeID string, sig []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sigs[seq] == nil {
+		b.sigs[seq] = map[string][]byte{}
+	}
+	b.sigs[seq][nodeID] = sig
+}
+
+func (b *cosignBook) count(seq int64) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.sigs[seq])
+}
+
+// SelfCoSign signs this node's own current chain head -- store.Backend's
+// ChainHead, not FSM.ChainHead -- with cfg.SigningKey and records it
+// locally under its own node id. Returns an error if no signing key is
+// configured or the backend has no chain head yet.
+func (n *Node) SelfCoSign(ctx context.Context) (seq int64, headHex string, sig []byte, err error) {
+	if n.cfg.SigningKey == nil {
+		return 0, "", nil, fmt.Errorf("cluster: no cosign signing key configured for node %s", n.cfg.NodeID)
+	}
+
+	seq, headHex, err = n.st.ChainHead(ctx)
+	if err != nil {
+		return 0, "", nil, err
+	}
+
+	sig = ed25519.Sign(n.cfg.SigningKey, cosignMsg(seq, headHex))
+	n.cosigns.add(seq, n.cfg.NodeID, sig)
+	return seq, headHex, sig, nil
+}
+
+// RecordCosignature validates and stores a peer's signature over
+// (seq, headHex). Returns an error if the signature doesn't verify.
+func (n *Node) RecordCosignature(seq int64, headHex, nodeID string, sig []byte, peerPubKey ed25519.PublicKey) error {
+	if !ed25519.Verify(peerPubKey, cosignMsg(seq, headHex), sig) {
+		return fmt.Errorf("cluster: co-signature from %s does not verify", nodeID)
+	}
+	n.cosigns.add(seq, nodeID, sig)
+	return nil
+}
+
+// QuorumSignatures returns how many distinct nodes have co-signed seq and
+// whether that meets a strict majority of total (the current configuration
+// size).
+func (n *Node) QuorumSignatures(seq int64, total int) (signed int, finalized bool) {
+	signed = n.cosigns.count(seq)
+	return signed, signed >= total/2+1
+}
+
+// GossipCosign self-co-signs this node's current chain head, then asks
+// every peer in cfg.PeerCosignAddrs to do the same (in parallel -- each
+// peer is independent and this is already a best-effort pass, so there's
+// no reason to let one slow peer delay the rest) and folds their
+// (verified) signatures into the local quorum set. A peer that's
+// unreachable, slow past cosignGossipTimeout, lagging, or whose public key
+// isn't configured just doesn't contribute this round, and
+// Status().Finalized reflects whatever quorum has been collected so far
+// -- a later commit (or a retried gossip round once the peer catches up)
+// can still finalize it. A no-op when no signing key is configured, so
+// it's safe to call unconditionally after every commit.
+func (n *Node) GossipCosign(ctx context.Context) error {
+	if n.cfg.SigningKey == nil {
+		return nil
+	}
+
+	seq, headHex, _, err := n.SelfCoSign(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := n.cosignClient
+	if client == nil {
+		client = HTTPCosignClient{}
+	}
+
+	gossipCtx, cancel := context.WithTimeout(ctx, cosignGossipTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for nodeID, addr := range n.cfg.PeerCosignAddrs {
+		pubKey, ok := n.cfg.PeerPublicKeys[nodeID]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(nodeID, addr string, pubKey ed25519.PublicKey) {
+			defer wg.Done()
+			peerSeq, peerHeadHex, gotNodeID, sig, err := client.RequestCosign(gossipCtx, addr)
+			if err != nil || gotNodeID != nodeID || peerSeq != seq || peerHeadHex != headHex {
+				return
+			}
+			_ = n.RecordCosignature(peerSeq, peerHeadHex, gotNodeID, sig, pubKey)
+		}(nodeID, addr, pubKey)
+	}
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultCosignHTTPTimeout bounds a single RequestCosign round-trip so a
+// down or black-holing peer can't stall GossipCosign (and, through it,
+// every Propose) past the point RequireLeader/the HTTP edge already gives
+// up on the surrounding write.
+const defaultCosignHTTPTimeout = 5 * time.Second
+
+// cosignResponse is the wire shape POST /v1/cluster/cosign returns: a
+// node's self-signature over its own current chain head. Shared between
+// the handler (PostCosign) and HTTPCosignClient so the two can't drift.
+type cosignResponse struct {
+	NodeID  string `json:"node_id"`
+	Seq     int64  `json:"seq"`
+	HeadHex string `json:"head_hash_hex"`
+	SigHex  string `json:"sig_hex"`
+}
+
+// CosignClient asks a peer to self-co-sign its own current chain head and
+// return the result, so the caller (normally the leader, right after a
+// commit, via GossipCosign) can fold it into its local quorum set via
+// RecordCosignature. HTTPCosignClient is the production implementation;
+// tests substitute an in-process one that calls straight into sibling
+// Nodes (see cluster_test.go) rather than standing up real listeners, the
+// same way raft.NewInmemTransport substitutes for TCP in this package's
+// tests.
+type CosignClient interface {
+	RequestCosign(ctx context.Context, addr string) (seq int64, headHex, nodeID string, sig []byte, err error)
+}
+
+// HTTPCosignClient is the production CosignClient: it POSTs to
+// {addr}/v1/cluster/cosign and parses the peer's self-signed response.
+type HTTPCosignClient struct {
+	HTTPClient *http.Client
+}
+
+func (c HTTPCosignClient) RequestCosign(ctx context.Context, addr string) (seq int64, headHex, nodeID string, sig []byte, err error) {
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: defaultCosignHTTPTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr+"/v1/cluster/cosign", nil)
+	if err != nil {
+		return 0, "", "", nil, err
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, "", "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", "", nil, fmt.Errorf("cluster: cosign request to %s: status %d", addr, resp.StatusCode)
+	}
+
+	var out cosignResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, "", "", nil, err
+	}
+	sig, err = hex.DecodeString(out.SigHex)
+	if err != nil {
+		return 0, "", "", nil, err
+	}
+	return out.Seq, out.HeadHex, out.NodeID, sig, nil
+}
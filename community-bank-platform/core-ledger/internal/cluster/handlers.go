@@ -0,0 +1,105 @@
+package cluster
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeErr(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]any{"error": msg})
+}
+
+// RequireLeader wraps a mutating handler (CreateAccount, PostTransfer) so
+// that any node other than the current Raft leader answers with 421
+// Misdirected Request and an X-Leader-Addr header instead of touching its
+// local backend -- followers must never apply a client write directly, only
+// via a committed Raft entry.
+func RequireLeader(n *Node, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !n.IsLeader() {
+			if addr := n.LeaderAddr(); addr != "" {
+				w.Header().Set("X-Leader-Addr", addr)
+			}
+			writeErr(w, http.StatusMisdirectedRequest, "not the leader")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GET /v1/cluster/status
+func (n *Node) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, n.Status(r.Context()))
+}
+
+type joinRequest struct {
+	NodeID string `json:"node_id"`
+	Addr   string `json:"addr"`
+}
+
+// POST /v1/cluster/join
+func (n *Node) PostJoin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req joinRequest
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.NodeID == "" || req.Addr == "" {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	if err := n.Join(req.NodeID, req.Addr); err != nil {
+		if err == ErrNotLeader {
+			if addr := n.LeaderAddr(); addr != "" {
+				w.Header().Set("X-Leader-Addr", addr)
+			}
+			writeErr(w, http.StatusMisdirectedRequest, "not the leader")
+			return
+		}
+		writeErr(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /v1/cluster/cosign -- any node (leader or follower) self-co-signs
+// its own current chain head and returns the result, so a peer gossiping
+// after a commit (see Node.GossipCosign) can fold it into its own quorum
+// set via RecordCosignature. Unlike CreateAccount/PostTransfer this is not
+// wrapped in RequireLeader: a follower's local chain head is exactly what
+// the leader needs co-signed, and SelfCoSign already fails closed (an
+// error, not a zero value) when no signing key is configured.
+func (n *Node) PostCosign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	seq, headHex, sig, err := n.SelfCoSign(r.Context())
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cosignResponse{
+		NodeID:  n.cfg.NodeID,
+		Seq:     seq,
+		HeadHex: headHex,
+		SigHex:  hex.EncodeToString(sig),
+	})
+}
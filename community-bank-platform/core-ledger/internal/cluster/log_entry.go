@@ -0,0 +1,50 @@
+package cluster
+
+import (
+	"github.com/google/uuid"
+
+	"core-ledger/internal/store"
+)
+
+// Op identifies a mutating store.Backend call that has been proposed for
+// replication. Only the two mutating Backend methods are replicated; reads
+// (Balance, VerifyEventChain) are served locally off whichever node handles
+// the request.
+type Op string
+
+const (
+	OpCreateAccount Op = "create_account"
+	OpPostTransfer  Op = "post_transfer"
+)
+
+// LogEntry is the canonical, JSON-encoded payload appended to the Raft log.
+// It carries everything a replica needs to reproduce the same
+// store.Backend call byte-for-byte, plus ClientSeq so a caller can tell
+// replayed proposals apart in logs/metrics.
+type LogEntry struct {
+	Op Op `json:"op"`
+
+	// OpCreateAccount fields.
+	Label    string `json:"label,omitempty"`
+	Currency string `json:"currency,omitempty"`
+
+	// OpPostTransfer fields.
+	FromAccountID  uuid.UUID          `json:"from_account_id,omitempty"`
+	ToAccountID    uuid.UUID          `json:"to_account_id,omitempty"`
+	AmountCents    int64              `json:"amount_cents,omitempty"`
+	ExternalRef    string             `json:"external_ref,omitempty"`
+	IdempotencyKey string             `json:"idempotency_key,omitempty"`
+	Attachments    []store.Attachment `json:"attachments,omitempty"`
+
+	CorrelationID string `json:"correlation_id"`
+	ClientSeq     uint64 `json:"client_seq"`
+}
+
+// ApplyResult is the value every replica's FSM.Apply returns for a given
+// LogEntry. raft.ApplyFuture.Response() on the proposing node yields the
+// leader's own copy of this struct.
+type ApplyResult struct {
+	AccountID uuid.UUID `json:"account_id,omitempty"`
+	TxID      uuid.UUID `json:"tx_id,omitempty"`
+	Err       error     `json:"-"`
+}
@@ -0,0 +1,242 @@
+// Package cluster turns a single-node store.Backend into a
+// Raft-replicated state machine: the leader accepts mutating calls at the
+// HTTP edge, replicates them as canonical log entries, and every node
+// (leader and followers alike) applies the committed entry to its own local
+// backend in log order -- the usual Raft replicated-state-machine shape,
+// analogous to how etcd/consul keep each node's local store in sync rather
+// than fanning writes out to one shared database.
+//
+// The local backend's chain head (store.Backend.ChainHead: the latest event
+// seq and its hash, see cosign.go) is threshold-co-signed by a quorum of
+// replicas with Ed25519, giving an
+// auditor a single signature set that attests "at least a majority of the
+// cluster agreed this history happened" independent of any one node's
+// Postgres.
+package cluster
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/raft"
+
+	"core-ledger/internal/store"
+)
+
+// ErrNotLeader is returned by Propose/Join when called against a node that
+// is not the current Raft leader. Callers (see httpapi wiring) turn this
+// into an HTTP 421 carrying the leader's address.
+var ErrNotLeader = errors.New("cluster: not the leader")
+
+// Config is the subset of Raft node configuration this package surfaces.
+// Everything else (heartbeat timeouts, etc.) uses raft.DefaultConfig.
+type Config struct {
+	NodeID    string
+	Bootstrap bool          // true only for the node that founds a new cluster
+	Peers     []raft.Server // initial configuration, used only when Bootstrap is set
+	DataDir   string        // snapshot store location; "" uses an in-memory discard store (tests)
+
+	// SigningKey, PeerPublicKeys, and PeerCosignAddrs configure threshold
+	// co-signing (see cosign.go): SigningKey signs this node's own
+	// attestations, PeerPublicKeys verifies peers' (keyed by NodeID), and
+	// PeerCosignAddrs is where this node asks each peer to self-co-sign
+	// after a commit (also keyed by NodeID, pointing at that peer's HTTP
+	// base address). All three are optional -- a nil SigningKey disables
+	// co-signing entirely, the backward-compatible default
+	// cluster_test.go's failover harness relies on. CosignClient overrides
+	// how peers are reached; nil uses HTTPCosignClient.
+	SigningKey      ed25519.PrivateKey
+	PeerPublicKeys  map[string]ed25519.PublicKey
+	PeerCosignAddrs map[string]string
+	CosignClient    CosignClient
+}
+
+// Node is one replica: a Raft instance plus the FSM wrapping its local
+// store.Backend.
+type Node struct {
+	cfg Config
+	st  store.Backend
+	raw *raft.Raft
+	fsm *FSM
+
+	cosigns      *cosignBook
+	cosignClient CosignClient
+}
+
+// New starts a Raft node over backend via transport. The caller owns
+// transport's lifecycle (and, for real deployments, wires it to a TCP
+// listener via raft.NewTCPTransport).
+func New(cfg Config, backend store.Backend, transport raft.Transport, seed func(string)) (*Node, error) {
+	raftCfg := raft.DefaultConfig()
+	raftCfg.LocalID = raft.ServerID(cfg.NodeID)
+
+	logStore := raft.NewInmemStore()
+	stableStore := raft.NewInmemStore()
+
+	var snapStore raft.SnapshotStore = raft.NewDiscardSnapshotStore()
+	if cfg.DataDir != "" {
+		fss, err := raft.NewFileSnapshotStore(cfg.DataDir, 2, os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("cluster: snapshot store: %w", err)
+		}
+		snapStore = fss
+	}
+
+	fsm := NewFSM(backend, seed)
+
+	raw, err := raft.NewRaft(raftCfg, fsm, logStore, stableStore, snapStore, transport)
+	if err != nil {
+		return nil, fmt.Errorf("cluster: new raft: %w", err)
+	}
+
+	n := &Node{cfg: cfg, st: backend, raw: raw, fsm: fsm, cosigns: newCosignBook(), cosignClient: cfg.CosignClient}
+
+	if cfg.Bootstrap {
+		servers := cfg.Peers
+		if len(servers) == 0 {
+			servers = []raft.Server{{ID: raftCfg.LocalID, Address: transport.LocalAddr()}}
+		}
+		if err := raw.BootstrapCluster(raft.Configuration{Servers: servers}).Error(); err != nil {
+			return nil, fmt.Errorf("cluster: bootstrap: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+// Backend returns the local store.Backend this node applies committed
+// entries to -- reads (Balance, VerifyEventChain) are safe to serve
+// straight off it on any node, including followers.
+func (n *Node) Backend() store.Backend { return n.st }
+
+func (n *Node) IsLeader() bool { return n.raw.State() == raft.Leader }
+
+// Shutdown stops this node's Raft participation. Used for graceful process
+// exit, and in tests to simulate a leader crashing mid-batch.
+func (n *Node) Shutdown() error {
+	return n.raw.Shutdown().Error()
+}
+
+// LeaderAddr returns the address the current leader is reachable at, or ""
+// if the cluster has no leader right now.
+func (n *Node) LeaderAddr() string {
+	addr, _ := n.raw.LeaderWithID()
+	return string(addr)
+}
+
+// Propose replicates entry and blocks until it is committed and applied
+// locally. Must be called on the leader; followers get ErrNotLeader so the
+// HTTP edge can redirect.
+func (n *Node) Propose(ctx context.Context, entry LogEntry) (ApplyResult, error) {
+	if !n.IsLeader() {
+		return ApplyResult{}, ErrNotLeader
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	timeout := 5 * time.Second
+	if dl, ok := ctx.Deadline(); ok {
+		if d := time.Until(dl); d > 0 {
+			timeout = d
+		}
+	}
+
+	future := n.raw.Apply(b, timeout)
+	if err := future.Error(); err != nil {
+		return ApplyResult{}, fmt.Errorf("cluster: apply: %w", err)
+	}
+
+	res, ok := future.Response().(ApplyResult)
+	if !ok {
+		return ApplyResult{}, fmt.Errorf("cluster: unexpected apply response type %T", future.Response())
+	}
+	if res.Err == nil {
+		// Best-effort: a co-signing failure (an unreachable peer, a
+		// missing key) must never fail the write itself, only leave this
+		// seq's quorum a little thinner until a later gossip round fills
+		// it in.
+		_ = n.GossipCosign(ctx)
+	}
+	return res, res.Err
+}
+
+// Join adds (nodeID, addr) as a voter. Must be called on the leader.
+func (n *Node) Join(nodeID, addr string) error {
+	if !n.IsLeader() {
+		return ErrNotLeader
+	}
+
+	cfgFuture := n.raw.GetConfiguration()
+	if err := cfgFuture.Error(); err != nil {
+		return fmt.Errorf("cluster: get configuration: %w", err)
+	}
+	for _, srv := range cfgFuture.Configuration().Servers {
+		if srv.ID == raft.ServerID(nodeID) && srv.Address == raft.ServerAddress(addr) {
+			return nil // already a member
+		}
+	}
+
+	if err := n.raw.AddVoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 10*time.Second).Error(); err != nil {
+		return fmt.Errorf("cluster: add voter: %w", err)
+	}
+	return nil
+}
+
+// Status is the JSON shape served by GET /v1/cluster/status.
+type Status struct {
+	NodeID       string   `json:"node_id"`
+	Leader       string   `json:"leader"`
+	Term         string   `json:"term"`
+	Peers        []string `json:"peers"`
+	AppliedIndex uint64   `json:"applied_index"`
+	ChainHead    string   `json:"chain_head"`
+	Finalized    bool     `json:"chain_head_finalized"`
+}
+
+// Status reports this node's view of the cluster. ChainHead/Finalized
+// describe the real store.Backend chain head (the value cosign.go
+// threshold-signs and an external auditor can check independently);
+// AppliedIndex is the FSM's own Raft-log replication counter, reported
+// purely for operational visibility into how far this node has replayed.
+//
+// Finalized is only ever driven to true on the node that called
+// GossipCosign, i.e. whichever node was leader at the time of the
+// triggering commit -- a follower's own cosignBook only gains entries it
+// answered PostCosign requests for, so polling Status on a follower can
+// under-report quorum. Poll the leader (or cmd/proof-verify against
+// Postgres directly) for an authoritative finalization check.
+func (n *Node) Status(ctx context.Context) Status {
+	var peers []string
+	if cfgFuture := n.raw.GetConfiguration(); cfgFuture.Error() == nil {
+		for _, srv := range cfgFuture.Configuration().Servers {
+			peers = append(peers, string(srv.Address))
+		}
+	}
+
+	idx, _ := n.fsm.ChainHead()
+
+	var head string
+	var finalized bool
+	if seq, hashHex, err := n.st.ChainHead(ctx); err == nil {
+		head = hashHex
+		_, finalized = n.QuorumSignatures(seq, len(peers))
+	}
+
+	return Status{
+		NodeID:       n.cfg.NodeID,
+		Leader:       n.LeaderAddr(),
+		Term:         n.raw.Stats()["term"],
+		Peers:        peers,
+		AppliedIndex: idx,
+		ChainHead:    head,
+		Finalized:    finalized,
+	}
+}
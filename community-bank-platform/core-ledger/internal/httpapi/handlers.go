@@ -5,20 +5,31 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"core-ledger/internal/domain"
 	"core-ledger/internal/store"
+	"core-ledger/pkg/eventproof"
 
 	"github.com/google/uuid"
 )
 
 type Handlers struct {
-	st *store.Store
+	st store.Backend
 }
 
-func NewHandlers(st *store.Store) *Handlers { return &Handlers{st: st} }
+// checkpointStore is the subset of *store.Store needed for the event-proof
+// endpoints. It is Postgres-specific (Merkle checkpoints live in a table),
+// so it's satisfied via a type assertion on h.st rather than folded into
+// store.Backend, which memstore also implements.
+type checkpointStore interface {
+	EventProofBySeq(ctx context.Context, seq int64) (eventproof.Proof, error)
+	LatestCheckpoint(ctx context.Context) (store.Checkpoint, error)
+}
+
+func NewHandlers(st store.Backend) *Handlers { return &Handlers{st: st} }
 
 func (h *Handlers) Healthz(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
@@ -74,6 +85,44 @@ func publicErrMessage(code int, err error) string {
 	return err.Error()
 }
 
+// errCodeForErr mirrors httpStatusForErr's switch, surfacing the same
+// sentinel-error classification as a stable machine-readable string rather
+// than an HTTP status code -- this is what the generated OpenAPI spec's
+// Error schema documents as the "code" discriminant.
+func errCodeForErr(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, store.ErrValidation):
+		return "validation_error"
+	case errors.Is(err, store.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, store.ErrIdempotencyConflict):
+		return "idempotency_conflict"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "internal_error"
+	}
+}
+
+type apiErrorBody struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// writeAPIErr maps err to an HTTP status and writes the {error, code}
+// envelope documented in the Error schema of build/openapi/ledger.json.
+func writeAPIErr(w http.ResponseWriter, err error) {
+	code := httpStatusForErr(err)
+	writeJSON(w, code, apiErrorBody{
+		Error: publicErrMessage(code, err),
+		Code:  errCodeForErr(err),
+	})
+}
+
 func (h *Handlers) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -96,8 +145,7 @@ func (h *Handlers) CreateAccount(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.st.CreateAccount(ctx, req.Label, strings.ToUpper(req.Currency), corr)
 	if err != nil {
-		code := httpStatusForErr(err)
-		writeErr(w, code, publicErrMessage(code, err))
+		writeAPIErr(w, err)
 		return
 	}
 
@@ -138,8 +186,7 @@ func (h *Handlers) PostTransfer(w http.ResponseWriter, r *http.Request) {
 		req.CorrelationID,
 	)
 	if err != nil {
-		code := httpStatusForErr(err)
-		writeErr(w, code, publicErrMessage(code, err))
+		writeAPIErr(w, err)
 		return
 	}
 
@@ -171,8 +218,7 @@ func (h *Handlers) GetBalanceByPath(w http.ResponseWriter, r *http.Request) {
 
 	cur, bal, err := h.st.Balance(ctx, accID)
 	if err != nil {
-		code := httpStatusForErr(err)
-		writeErr(w, code, publicErrMessage(code, err))
+		writeAPIErr(w, err)
 		return
 	}
 
@@ -182,3 +228,96 @@ func (h *Handlers) GetBalanceByPath(w http.ResponseWriter, r *http.Request) {
 		BalanceCents: bal,
 	})
 }
+
+type eventProofResponse struct {
+	Seq      uint64               `json:"seq"`
+	Leaf     string               `json:"leaf"`
+	Siblings []eventproof.Sibling `json:"siblings"`
+	SeqLo    uint64               `json:"seq_lo"`
+	SeqHi    uint64               `json:"seq_hi"`
+	Root     string               `json:"root"`
+}
+
+// GET /v1/events/{seq}/proof
+func (h *Handlers) GetEventProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	cps, ok := h.st.(checkpointStore)
+	if !ok {
+		writeErr(w, http.StatusNotImplemented, "event proofs are not available on this backend")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/events/")
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[1] != "proof" {
+		writeErr(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	seq, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || seq <= 0 {
+		writeErr(w, http.StatusBadRequest, "invalid seq")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	proof, err := cps.EventProofBySeq(ctx, seq)
+	if err != nil {
+		writeAPIErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, eventProofResponse{
+		Seq:      proof.Seq,
+		Leaf:     proof.Leaf,
+		Siblings: proof.Siblings,
+		SeqLo:    proof.SeqLo,
+		SeqHi:    proof.SeqHi,
+		Root:     proof.Root,
+	})
+}
+
+type checkpointResponse struct {
+	SeqLo        int64  `json:"seq_lo"`
+	SeqHi        int64  `json:"seq_hi"`
+	Root         string `json:"root"`
+	SignatureHex string `json:"signature_hex,omitempty"`
+	BuiltAt      string `json:"built_at"`
+}
+
+// GET /v1/checkpoints/latest
+func (h *Handlers) GetLatestCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	cps, ok := h.st.(checkpointStore)
+	if !ok {
+		writeErr(w, http.StatusNotImplemented, "checkpoints are not available on this backend")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	cp, err := cps.LatestCheckpoint(ctx)
+	if err != nil {
+		writeAPIErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, checkpointResponse{
+		SeqLo:        cp.SeqLo,
+		SeqHi:        cp.SeqHi,
+		Root:         cp.RootHex,
+		SignatureHex: cp.SignatureHex,
+		BuiltAt:      cp.BuiltAt.UTC().Format(time.RFC3339Nano),
+	})
+}
@@ -12,6 +12,8 @@ func Router(h *Handlers) http.Handler {
 	mux.HandleFunc("/v1/accounts", h.CreateAccount)     // POST
 	mux.HandleFunc("/v1/transfers", h.PostTransfer)     // POST
 	mux.HandleFunc("/v1/accounts/", h.GetBalanceByPath) // GET /v1/accounts/{uuid}/balance
+	mux.HandleFunc("/v1/events/", h.GetEventProof)      // GET /v1/events/{seq}/proof
+	mux.HandleFunc("/v1/checkpoints/latest", h.GetLatestCheckpoint)
 
 	// Backpressure at the edge.
 	// Prevents unbounded goroutine/pool queueing when DB is saturated.
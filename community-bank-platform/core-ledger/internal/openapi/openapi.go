@@ -0,0 +1,204 @@
+// Package openapi generates the OpenAPI 3.1 document describing httpapi's
+// stable HTTP surface. Request/response schemas are derived by reflecting
+// over the internal/domain types httpapi actually encodes and decodes, and
+// the Error schema is a discriminated union keyed off the same sentinel
+// errors httpStatusForErr switches on -- so the spec can't silently drift
+// from the handlers, the way a hand-maintained document would.
+//
+// `make docsgen` regenerates build/openapi/ledger.json; a test in this
+// package fails the build if the checked-in file has drifted.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/domain"
+)
+
+// Schema is the subset of JSON Schema (as embedded in OpenAPI 3.1) this
+// generator needs: plain object/primitive shapes plus $ref.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Document is the root OpenAPI 3.1 object, trimmed to the fields this
+// generator populates.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// schemaForStruct derives an object Schema from a domain request/response
+// struct, walking its fields in declaration order so `required` lines up
+// with how the struct actually reads.
+func schemaForStruct(v any) *Schema {
+	t := reflect.TypeOf(v)
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		s.Properties[name] = schemaForField(f.Type)
+		s.Required = append(s.Required, name)
+	}
+	return s
+}
+
+func schemaForField(t reflect.Type) *Schema {
+	switch {
+	case t == uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case t.Kind() == reflect.String:
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Int64, t.Kind() == reflect.Int, t.Kind() == reflect.Int32:
+		return &Schema{Type: "integer", Format: "int64"}
+	default:
+		return &Schema{}
+	}
+}
+
+func ref(name string) *Schema { return &Schema{Ref: "#/components/schemas/" + name} }
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// errorSchema models httpapi's {error, code} envelope (see
+// httpapi.apiErrorBody / writeAPIErr) as a discriminated union: "code" is
+// one of errCodeForErr's outputs, each tracing back to one sentinel error
+// httpStatusForErr switches on. Responses written by httpapi.writeErr
+// (malformed JSON, wrong method, bad path parameter, unsupported backend)
+// carry only "error", so "code" is not required.
+func errorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {Type: "string"},
+			"code": {
+				Type: "string",
+				Enum: []string{
+					"validation_error",
+					"not_found",
+					"idempotency_conflict",
+					"timeout",
+					"canceled",
+					"internal_error",
+				},
+			},
+		},
+		Required: []string{"error"},
+	}
+}
+
+func errorResponse() Response {
+	return Response{Description: "error", Content: jsonContent(ref("Error"))}
+}
+
+// Generate builds the OpenAPI 3.1 document for httpapi's store.Backend-
+// backed endpoints: create account, post transfer, get balance. The
+// Postgres-only checkpoint/event-proof endpoints (gated behind httpapi's
+// checkpointStore type assertion) are deliberately out of scope -- they
+// aren't part of the store.Backend contract every deployment satisfies, so
+// documenting them here would promise a guarantee the spec can't keep.
+func Generate() *Document {
+	return &Document{
+		OpenAPI: "3.1.0",
+		Info:    Info{Title: "core-ledger", Version: "v1"},
+		Paths: map[string]*PathItem{
+			"/v1/accounts": {
+				Post: &Operation{
+					OperationID: "CreateAccount",
+					Summary:     "Create an account",
+					RequestBody: &RequestBody{Required: true, Content: jsonContent(ref("CreateAccountRequest"))},
+					Responses: map[string]Response{
+						"201":     {Description: "account created", Content: jsonContent(ref("CreateAccountResponse"))},
+						"default": errorResponse(),
+					},
+				},
+			},
+			"/v1/accounts/{account_id}/balance": {
+				Get: &Operation{
+					OperationID: "GetBalance",
+					Summary:     "Get an account's balance",
+					Responses: map[string]Response{
+						"200":     {Description: "balance", Content: jsonContent(ref("BalanceResponse"))},
+						"default": errorResponse(),
+					},
+				},
+			},
+			"/v1/transfers": {
+				Post: &Operation{
+					OperationID: "PostTransfer",
+					Summary:     "Post a balanced transfer between two accounts",
+					RequestBody: &RequestBody{Required: true, Content: jsonContent(ref("PostTransferRequest"))},
+					Responses: map[string]Response{
+						"201":     {Description: "transfer posted", Content: jsonContent(ref("PostTransferResponse"))},
+						"default": errorResponse(),
+					},
+				},
+			},
+		},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"CreateAccountRequest":  schemaForStruct(domain.CreateAccountRequest{}),
+				"CreateAccountResponse": schemaForStruct(domain.CreateAccountResponse{}),
+				"PostTransferRequest":   schemaForStruct(domain.PostTransferRequest{}),
+				"PostTransferResponse":  schemaForStruct(domain.PostTransferResponse{}),
+				"BalanceResponse":       schemaForStruct(domain.BalanceResponse{}),
+				"Error":                 errorSchema(),
+			},
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"core-ledger/internal/openapi"
+)
+
+// TestGenerate_MatchesCommittedSpec is the drift check `make docsgen` is
+// supposed to keep green: regenerate the document in-process and diff it
+// byte-for-byte against build/openapi/ledger.json.
+func TestGenerate_MatchesCommittedSpec(t *testing.T) {
+	got, err := json.MarshalIndent(openapi.Generate(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got = append(got, '\n')
+
+	want, err := os.ReadFile(filepath.Join("..", "..", "build", "openapi", "ledger.json"))
+	if err != nil {
+		t.Fatalf("read committed spec: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Fatalf("generated OpenAPI doc has drifted from build/openapi/ledger.json; run `make docsgen` and commit the result")
+	}
+}
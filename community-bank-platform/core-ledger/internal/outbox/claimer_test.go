@@ -0,0 +1,180 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/outbox"
+	"core-ledger/internal/store"
+)
+
+// TestClaimer_ClaimPublishResume exercises the lifecycle a real Relay drives:
+// claim an unpublished batch, publish it, and confirm ResumeToken moves past
+// it. It also checks that a second ClaimAndPublish call, after the first
+// batch is published, doesn't reclaim it.
+func TestClaimer_ClaimPublishResume(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-claimer-" + uuid.NewString()
+	if _, err := s.CreateAccount(ctx, "claimer-"+uuid.NewString(), "USD", corr); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	claimer := outbox.NewClaimer(pool)
+
+	beforeToken, err := claimer.ResumeToken(ctx)
+	if err != nil {
+		t.Fatalf("ResumeToken (before): %v", err)
+	}
+
+	var maxSeq int64
+	var seqs []int64
+	var replayFrom int64
+	n, err := claimer.ClaimAndPublish(ctx, 1000, func(ctx context.Context, batch []outbox.Message) error {
+		if len(batch) == 0 {
+			t.Fatal("expected at least one unpublished row to claim")
+		}
+		seqs = make([]int64, len(batch))
+		for i, m := range batch {
+			seqs[i] = m.Seq
+			if m.Seq > maxSeq {
+				maxSeq = m.Seq
+			}
+		}
+		replayFrom = batch[0].Seq
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ClaimAndPublish: %v", err)
+	}
+	if n != len(seqs) {
+		t.Fatalf("ClaimAndPublish returned %d, want %d", n, len(seqs))
+	}
+
+	afterToken, err := claimer.ResumeToken(ctx)
+	if err != nil {
+		t.Fatalf("ResumeToken (after): %v", err)
+	}
+	if afterToken <= maxSeq {
+		t.Fatalf("expected resume token past %d once published, got %d", maxSeq, afterToken)
+	}
+	if afterToken <= beforeToken {
+		t.Fatalf("expected resume token to advance: before=%d after=%d", beforeToken, afterToken)
+	}
+
+	replayed, err := claimer.Replay(ctx, replayFrom, len(seqs)+10)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if len(replayed) < len(seqs) {
+		t.Fatalf("expected Replay to return the published rows too, got %d want >= %d", len(replayed), len(seqs))
+	}
+
+	_, err = claimer.ClaimAndPublish(ctx, 1000, func(ctx context.Context, batch []outbox.Message) error {
+		for _, m := range batch {
+			for _, published := range seqs {
+				if m.Seq == published {
+					t.Errorf("seq %d was reclaimed after it was published", m.Seq)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, outbox.ErrNoRows) {
+		// Other account-creation tests may run concurrently and leave their
+		// own unpublished rows behind; only a real error is a failure here.
+		t.Fatalf("ClaimAndPublish (after publish): %v", err)
+	}
+}
+
+// TestClaimer_ConcurrentClaimAndPublish_NoDoubleDelivery runs several
+// claimers against the same unpublished rows at once and checks that every
+// row is delivered exactly once: ClaimAndPublish must hold its row locks
+// across the publish callback, not just across the claiming SELECT, or two
+// concurrent ledger-relay processes could both see a row as unpublished and
+// publish it twice before either commits.
+func TestClaimer_ConcurrentClaimAndPublish_NoDoubleDelivery(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-claimer-conc-" + uuid.NewString()
+	const rows = 20
+	for i := 0; i < rows; i++ {
+		if _, err := s.CreateAccount(ctx, "claimer-conc-"+uuid.NewString(), "USD", corr); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+
+	claimer := outbox.NewClaimer(pool)
+
+	var mu sync.Mutex
+	seen := map[int64]int{}
+
+	const claimers = 8
+	var wg sync.WaitGroup
+	wg.Add(claimers)
+	for i := 0; i < claimers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				_, err := claimer.ClaimAndPublish(ctx, 1, func(ctx context.Context, batch []outbox.Message) error {
+					mu.Lock()
+					for _, m := range batch {
+						seen[m.Seq]++
+					}
+					mu.Unlock()
+					return nil
+				})
+				if err != nil {
+					if errors.Is(err, outbox.ErrNoRows) {
+						return
+					}
+					t.Errorf("ClaimAndPublish: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for seq, count := range seen {
+		if count > 1 {
+			t.Fatalf("seq=%d delivered %d times, want at most once", seq, count)
+		}
+	}
+}
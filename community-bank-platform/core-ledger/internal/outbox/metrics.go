@@ -0,0 +1,51 @@
+package outbox
+
+import "sync/atomic"
+
+// Metrics tracks back-pressure signals for a running Relay: whether it's
+// keeping up with event_log (Lag), and how many messages/batches have
+// succeeded or failed since the process started. It's deliberately a plain
+// struct rather than a global registry -- cmd/ledger-relay decides how (or
+// whether) to expose it, e.g. via expvar.Publish or a JSON status endpoint.
+type Metrics struct {
+	publishedTotal int64
+	failedTotal    int64
+	batchesTotal   int64
+	lag            int64
+}
+
+func (m *Metrics) recordSuccess(n int, lag int64) {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.publishedTotal, int64(n))
+	atomic.AddInt64(&m.batchesTotal, 1)
+	atomic.StoreInt64(&m.lag, lag)
+}
+
+func (m *Metrics) recordFailure(n int) {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.failedTotal, int64(n))
+}
+
+// Snapshot is a point-in-time read of Metrics, safe to marshal to JSON.
+type Snapshot struct {
+	PublishedTotal int64 `json:"published_total"`
+	FailedTotal    int64 `json:"failed_total"`
+	BatchesTotal   int64 `json:"batches_total"`
+	Lag            int64 `json:"lag"`
+}
+
+func (m *Metrics) Snapshot() Snapshot {
+	if m == nil {
+		return Snapshot{}
+	}
+	return Snapshot{
+		PublishedTotal: atomic.LoadInt64(&m.publishedTotal),
+		FailedTotal:    atomic.LoadInt64(&m.failedTotal),
+		BatchesTotal:   atomic.LoadInt64(&m.batchesTotal),
+		Lag:            atomic.LoadInt64(&m.lag),
+	}
+}
@@ -0,0 +1,253 @@
+// Package outbox tails the event_outbox staging table (written by
+// internal/store's insertEvent, inside the same transaction as the
+// event_log insert it mirrors) and publishes each row to a pluggable Sink.
+// cmd/ledger-relay is the process that wires a Claimer to a Sink and runs
+// the loop; this package holds the parts that don't need a CLI around them.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Message is one event_outbox row, ready to publish. Headers carries seq +
+// hash_hex (and its neighbors) so a consumer can re-run the same chain check
+// cmd/proof-verify does, without a DB connection of its own.
+type Message struct {
+	Seq              int64
+	AggregateID      string
+	PayloadCanonical string
+	HashHex          string
+	PrevHashHex      string
+	Headers          json.RawMessage
+}
+
+// Sink publishes a claimed batch of messages, in order, to wherever
+// downstream consumers actually read from (a Kafka topic, a NATS
+// JetStream subject, an HTTP webhook...). Publish must not return nil
+// unless every message in the batch was durably accepted by the broker --
+// the relay only marks a batch published after Publish succeeds, so a
+// partial accept that returns nil would silently drop the rest of the batch.
+type Sink interface {
+	Publish(ctx context.Context, batch []Message) error
+}
+
+var ErrNoRows = errors.New("outbox: no unpublished rows")
+
+// Claimer claims and marks event_outbox rows against a real Postgres pool.
+type Claimer struct {
+	db *pgxpool.Pool
+}
+
+func NewClaimer(db *pgxpool.Pool) *Claimer {
+	return &Claimer{db: db}
+}
+
+// ClaimAndPublish locks up to limit unpublished rows with FOR UPDATE SKIP
+// LOCKED, calls publish with that batch, and -- only if publish returns nil
+// -- stamps them published_at, all inside the one transaction holding the
+// row locks. Holding the locks across publish (not just across the claiming
+// SELECT) is what actually makes "multiple ledger-relay processes can run
+// concurrently against the same table without claiming the same row twice"
+// true: a second claimer's FOR UPDATE SKIP LOCKED skips these rows until
+// this transaction commits or rolls back, so a crash (or a publish error,
+// which rolls back) leaves them free for another relay to pick up, and a
+// concurrent in-flight claimer never sees them as unpublished in the first
+// place. Returns ErrNoRows if there was nothing to claim; publish is never
+// called in that case.
+func (c *Claimer) ClaimAndPublish(ctx context.Context, limit int, publish func(ctx context.Context, batch []Message) error) (int, error) {
+	tx, err := c.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: pgx.ReadWrite})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT seq, aggregate_id, payload_canonical, hash_hex, COALESCE(prev_hash_hex,''), headers_jsonb
+		  FROM event_outbox
+		 WHERE published_at IS NULL
+		 ORDER BY seq ASC
+		 LIMIT $1
+		   FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Seq, &m.AggregateID, &m.PayloadCanonical, &m.HashHex, &m.PrevHashHex, &m.Headers); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, m)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	if len(batch) == 0 {
+		return 0, ErrNoRows
+	}
+
+	if err := publish(ctx, batch); err != nil {
+		return 0, err
+	}
+
+	seqs := make([]int64, len(batch))
+	for i, m := range batch {
+		seqs[i] = m.Seq
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE event_outbox
+		   SET published_at = now()
+		 WHERE seq = ANY($1) AND published_at IS NULL
+	`, seqs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
+
+// ResumeToken reports where a fresh consumer should start: the seq of the
+// oldest row not yet published, or (if the relay is fully caught up) one
+// past the newest published row. Either way, replaying from this seq never
+// skips a message.
+func (c *Claimer) ResumeToken(ctx context.Context) (int64, error) {
+	var nextUnpublished *int64
+	err := c.db.QueryRow(ctx, `SELECT min(seq) FROM event_outbox WHERE published_at IS NULL`).Scan(&nextUnpublished)
+	if err != nil {
+		return 0, err
+	}
+	if nextUnpublished != nil {
+		return *nextUnpublished, nil
+	}
+
+	var maxPublished *int64
+	err = c.db.QueryRow(ctx, `SELECT max(seq) FROM event_outbox WHERE published_at IS NOT NULL`).Scan(&maxPublished)
+	if err != nil {
+		return 0, err
+	}
+	if maxPublished == nil {
+		return 1, nil
+	}
+	return *maxPublished + 1, nil
+}
+
+// Replay returns up to limit outbox rows starting at fromSeq (inclusive),
+// published or not. It backs the resume-token HTTP endpoint's companion
+// replay call: a consumer that reconnects with a stale cursor re-fetches
+// the rows it may have missed directly from Postgres instead of relying on
+// broker-side replay.
+func (c *Claimer) Replay(ctx context.Context, fromSeq int64, limit int) ([]Message, error) {
+	rows, err := c.db.Query(ctx, `
+		SELECT seq, aggregate_id, payload_canonical, hash_hex, COALESCE(prev_hash_hex,''), headers_jsonb
+		  FROM event_outbox
+		 WHERE seq >= $1
+		 ORDER BY seq ASC
+		 LIMIT $2
+	`, fromSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batch []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Seq, &m.AggregateID, &m.PayloadCanonical, &m.HashHex, &m.PrevHashHex, &m.Headers); err != nil {
+			return nil, err
+		}
+		batch = append(batch, m)
+	}
+	return batch, rows.Err()
+}
+
+// Relay repeatedly claims batches and publishes them to a Sink, tracking
+// enough state (via Metrics) for an operator to see whether it's keeping up.
+type Relay struct {
+	Claimer      *Claimer
+	Sink         Sink
+	BatchSize    int
+	PollInterval time.Duration
+	Metrics      *Metrics
+}
+
+// Run claims and publishes batches until ctx is canceled, then returns
+// ctx.Err(); it returns no other error. A failed batch (a Sink error
+// included) is passed to onErr and retried after PollInterval rather than
+// aborting the loop, since a transient broker outage shouldn't take the
+// relay process down.
+func (r *Relay) Run(ctx context.Context, onErr func(error)) error {
+	if r.BatchSize <= 0 {
+		r.BatchSize = 100
+	}
+	if r.PollInterval <= 0 {
+		r.PollInterval = time.Second
+	}
+
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for {
+				n, err := r.publishOnce(ctx)
+				if err != nil {
+					if !errors.Is(err, ErrNoRows) && onErr != nil {
+						onErr(err)
+					}
+					break
+				}
+				if n < r.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (r *Relay) publishOnce(ctx context.Context) (int, error) {
+	var failed int
+	n, err := r.Claimer.ClaimAndPublish(ctx, r.BatchSize, func(ctx context.Context, batch []Message) error {
+		if err := r.Sink.Publish(ctx, batch); err != nil {
+			failed = len(batch)
+			return fmt.Errorf("outbox: publish batch of %d: %w", len(batch), err)
+		}
+		return nil
+	})
+	if err != nil {
+		if failed > 0 {
+			r.Metrics.recordFailure(failed)
+		}
+		return 0, err
+	}
+
+	lag, err := r.lag(ctx)
+	if err == nil {
+		r.Metrics.recordSuccess(n, lag)
+	} else {
+		r.Metrics.recordSuccess(n, 0)
+	}
+	return n, nil
+}
+
+func (r *Relay) lag(ctx context.Context) (int64, error) {
+	var lag int64
+	err := r.Claimer.db.QueryRow(ctx, `SELECT count(*) FROM event_outbox WHERE published_at IS NULL`).Scan(&lag)
+	return lag, err
+}
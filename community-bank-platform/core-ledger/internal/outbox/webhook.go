@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink publishes each batch as a single JSON POST, HMAC-signed so the
+// receiver can confirm the request actually came from this ledger (and
+// wasn't replayed with a tampered body) without a shared TLS client cert.
+// It's the one concrete Sink this package ships; Kafka/NATS JetStream sinks
+// can implement the same Sink interface without touching the relay loop.
+type WebhookSink struct {
+	URL    string
+	Secret []byte
+	Client *http.Client
+}
+
+func NewWebhookSink(url string, secret []byte) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Secret: secret,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type webhookMessage struct {
+	Seq              int64           `json:"seq"`
+	AggregateID      string          `json:"aggregate_id"`
+	PayloadCanonical string          `json:"payload_canonical"`
+	HashHex          string          `json:"hash_hex"`
+	PrevHashHex      string          `json:"prev_hash_hex"`
+	Headers          json.RawMessage `json:"headers"`
+}
+
+type webhookBody struct {
+	Messages []webhookMessage `json:"messages"`
+}
+
+// signatureHex is sha256 HMAC over the exact bytes sent as the request body,
+// hex-encoded. The receiver recomputes it with the shared secret and
+// compares against the X-Ledger-Signature header before trusting the batch.
+func signatureHex(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookSink) Publish(ctx context.Context, batch []Message) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	msgs := make([]webhookMessage, len(batch))
+	for i, m := range batch {
+		msgs[i] = webhookMessage{
+			Seq:              m.Seq,
+			AggregateID:      m.AggregateID,
+			PayloadCanonical: m.PayloadCanonical,
+			HashHex:          m.HashHex,
+			PrevHashHex:      m.PrevHashHex,
+			Headers:          m.Headers,
+		}
+	}
+
+	body, err := json.Marshal(webhookBody{Messages: msgs})
+	if err != nil {
+		return fmt.Errorf("outbox: marshal webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("outbox: build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.Secret) > 0 {
+		req.Header.Set("X-Ledger-Signature", "sha256="+signatureHex(w.Secret, body))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("outbox: webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outbox: webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
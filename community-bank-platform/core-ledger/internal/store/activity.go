@@ -0,0 +1,265 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Direction names a ledger_entry side. See normalizeDirection for the
+// string form actually stored in the DB.
+type Direction string
+
+const (
+	DirectionDebit  Direction = "DEBIT"
+	DirectionCredit Direction = "CREDIT"
+)
+
+// defaultActivityLimit and maxActivityLimit bound ActivityFilter.Limit: 0
+// (unset) falls back to the default, anything above the max is clamped.
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 500
+)
+
+// ActivityFilter selects a page of ledger_entry rows for one or more
+// accounts. AccountIDs is mandatory: every index Store.Activity can use
+// leads with account_id (see migration 0007), so a filter that omits it
+// has no supporting index and is rejected rather than silently falling
+// back to a sequential scan.
+type ActivityFilter struct {
+	AccountIDs        []uuid.UUID
+	Currencies        []string
+	Counterparties    []uuid.UUID
+	Directions        []Direction
+	MinCents          *int64
+	MaxCents          *int64
+	Since             time.Time
+	Until             time.Time
+	ExternalRefPrefix string
+	Cursor            string
+	Limit             int
+}
+
+// ActivityEntry is one ledger_entry leg joined back to its ledger_tx.
+// Counterparty is only populated for a plain 2-leg transfer (the shape
+// PostTransfer/ReverseTransfer write); for a PostJournal tx with more than
+// two legs there is no single counterparty, so it's left as uuid.Nil.
+type ActivityEntry struct {
+	EntryID      uuid.UUID
+	TxID         uuid.UUID
+	AccountID    uuid.UUID
+	Counterparty uuid.UUID
+	Direction    string
+	AmountCents  int64
+	Currency     string
+	ExternalRef  string
+	PostedAt     time.Time
+}
+
+// ActivityAggregates summarizes the entries actually returned on a page,
+// so a caller building a statement doesn't need a second round-trip.
+type ActivityAggregates struct {
+	SumCreditsCents        int64
+	SumDebitsCents         int64
+	DistinctCounterparties int
+}
+
+// ActivityPage is one page of Store.Activity results. NextCursor is empty
+// once there are no further rows for the filter.
+type ActivityPage struct {
+	Entries    []ActivityEntry
+	Aggregates ActivityAggregates
+	NextCursor string
+}
+
+// activityCursor is the decoded form of ActivityFilter.Cursor /
+// ActivityPage.NextCursor: the same (posted_at, tx_id, entry_id) triple the
+// keyset pagination orders by, so a page boundary is deterministic even
+// under concurrent writes to the append-only log.
+type activityCursor struct {
+	PostedAt time.Time `json:"posted_at"`
+	TxID     uuid.UUID `json:"tx_id"`
+	EntryID  uuid.UUID `json:"entry_id"`
+}
+
+func encodeActivityCursor(c activityCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeActivityCursor(s string) (activityCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return activityCursor{}, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	var c activityCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return activityCursor{}, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	return c, nil
+}
+
+// Activity returns one page of ledger activity matching f. AccountIDs must
+// be non-empty; every other field narrows the rows an account-leading
+// index already selected.
+func (s *Store) Activity(ctx context.Context, f ActivityFilter) (ActivityPage, error) {
+	if len(f.AccountIDs) == 0 {
+		return ActivityPage{}, fmt.Errorf("%w: activity filter requires at least one account_id (no supporting index otherwise)", ErrValidation)
+	}
+	for _, d := range f.Directions {
+		if d != DirectionDebit && d != DirectionCredit {
+			return ActivityPage{}, fmt.Errorf("%w: invalid direction %q", ErrValidation, d)
+		}
+	}
+	if f.MinCents != nil && *f.MinCents < 0 {
+		return ActivityPage{}, fmt.Errorf("%w: min_cents must be non-negative", ErrValidation)
+	}
+	if f.MaxCents != nil && *f.MaxCents < 0 {
+		return ActivityPage{}, fmt.Errorf("%w: max_cents must be non-negative", ErrValidation)
+	}
+	if f.MinCents != nil && f.MaxCents != nil && *f.MinCents > *f.MaxCents {
+		return ActivityPage{}, fmt.Errorf("%w: min_cents exceeds max_cents", ErrValidation)
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultActivityLimit
+	}
+	if limit > maxActivityLimit {
+		limit = maxActivityLimit
+	}
+
+	var cursor *activityCursor
+	if f.Cursor != "" {
+		c, err := decodeActivityCursor(f.Cursor)
+		if err != nil {
+			return ActivityPage{}, err
+		}
+		cursor = &c
+	}
+
+	where := []string{"e.account_id = ANY($1)"}
+	args := []any{f.AccountIDs}
+
+	if len(f.Currencies) > 0 {
+		where = append(where, fmt.Sprintf("e.currency = ANY($%d)", len(args)+1))
+		args = append(args, f.Currencies)
+	}
+	if len(f.Directions) > 0 {
+		dirs := make([]string, len(f.Directions))
+		for i, d := range f.Directions {
+			dirs[i] = string(d)
+		}
+		where = append(where, fmt.Sprintf("e.direction = ANY($%d)", len(args)+1))
+		args = append(args, dirs)
+	}
+	if f.MinCents != nil {
+		where = append(where, fmt.Sprintf("e.amount_cents >= $%d", len(args)+1))
+		args = append(args, *f.MinCents)
+	}
+	if f.MaxCents != nil {
+		where = append(where, fmt.Sprintf("e.amount_cents <= $%d", len(args)+1))
+		args = append(args, *f.MaxCents)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, fmt.Sprintf("e.posted_at >= $%d", len(args)+1))
+		args = append(args, f.Since)
+	}
+	if !f.Until.IsZero() {
+		where = append(where, fmt.Sprintf("e.posted_at <= $%d", len(args)+1))
+		args = append(args, f.Until)
+	}
+	if f.ExternalRefPrefix != "" {
+		where = append(where, fmt.Sprintf("t.external_ref LIKE $%d", len(args)+1))
+		args = append(args, escapeLikePrefix(f.ExternalRefPrefix)+"%")
+	}
+	if len(f.Counterparties) > 0 {
+		where = append(where, fmt.Sprintf("cp.account_id = ANY($%d)", len(args)+1))
+		args = append(args, f.Counterparties)
+	}
+	if cursor != nil {
+		where = append(where, fmt.Sprintf(
+			"(e.posted_at, e.tx_id, e.entry_id) < ($%d, $%d, $%d)",
+			len(args)+1, len(args)+2, len(args)+3,
+		))
+		args = append(args, cursor.PostedAt, cursor.TxID, cursor.EntryID)
+	}
+
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT e.entry_id, e.tx_id, e.account_id, e.direction, e.amount_cents, e.currency, e.posted_at, t.external_ref,
+		       COALESCE(cp.account_id, '00000000-0000-0000-0000-000000000000') AS counterparty_account_id
+		FROM ledger_entry e
+		JOIN ledger_tx t ON t.tx_id = e.tx_id
+		LEFT JOIN ledger_entry cp
+		       ON cp.tx_id = e.tx_id
+		      AND cp.entry_id <> e.entry_id
+		      AND (SELECT count(*) FROM ledger_entry x WHERE x.tx_id = e.tx_id) = 2
+		WHERE %s
+		ORDER BY e.posted_at DESC, e.tx_id DESC, e.entry_id DESC
+		LIMIT $%d
+	`, strings.Join(where, " AND "), len(args))
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return ActivityPage{}, err
+	}
+	defer rows.Close()
+
+	var entries []ActivityEntry
+	for rows.Next() {
+		var e ActivityEntry
+		if err := rows.Scan(&e.EntryID, &e.TxID, &e.AccountID, &e.Direction, &e.AmountCents, &e.Currency, &e.PostedAt, &e.ExternalRef, &e.Counterparty); err != nil {
+			return ActivityPage{}, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return ActivityPage{}, err
+	}
+
+	page := ActivityPage{}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		nextCursor, err := encodeActivityCursor(activityCursor{PostedAt: last.PostedAt, TxID: last.TxID, EntryID: last.EntryID})
+		if err != nil {
+			return ActivityPage{}, err
+		}
+		page.NextCursor = nextCursor
+	}
+	page.Entries = entries
+
+	counterparties := map[uuid.UUID]bool{}
+	for _, e := range entries {
+		switch e.Direction {
+		case string(DirectionCredit):
+			page.Aggregates.SumCreditsCents += e.AmountCents
+		case string(DirectionDebit):
+			page.Aggregates.SumDebitsCents += e.AmountCents
+		}
+		if e.Counterparty != uuid.Nil {
+			counterparties[e.Counterparty] = true
+		}
+	}
+	page.Aggregates.DistinctCounterparties = len(counterparties)
+
+	return page, nil
+}
+
+// escapeLikePrefix escapes LIKE metacharacters in a user-supplied prefix so
+// ExternalRefPrefix is matched literally up to the trailing wildcard this
+// package adds.
+func escapeLikePrefix(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(prefix)
+}
@@ -0,0 +1,94 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestActivity_FiltersAndPaginatesByKeyset(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-activity-" + uuid.NewString()
+
+	alice, err := s.CreateAccount(ctx, "activity-alice-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount alice: %v", err)
+	}
+	bob, err := s.CreateAccount(ctx, "activity-bob-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount bob: %v", err)
+	}
+	carol, err := s.CreateAccount(ctx, "activity-carol-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount carol: %v", err)
+	}
+
+	if _, err := s.PostTransfer(ctx, alice, bob, 1000, "EUR", "ext-"+uuid.NewString(), "idem-"+uuid.NewString(), corr); err != nil {
+		t.Fatalf("PostTransfer 1: %v", err)
+	}
+	if _, err := s.PostTransfer(ctx, alice, carol, 2000, "EUR", "ext-"+uuid.NewString(), "idem-"+uuid.NewString(), corr); err != nil {
+		t.Fatalf("PostTransfer 2: %v", err)
+	}
+
+	page, err := s.Activity(ctx, store.ActivityFilter{AccountIDs: []uuid.UUID{alice}})
+	if err != nil {
+		t.Fatalf("Activity: %v", err)
+	}
+	if len(page.Entries) != 2 {
+		t.Fatalf("expected 2 entries for alice, got %d", len(page.Entries))
+	}
+	if page.Aggregates.SumDebitsCents != 3000 {
+		t.Fatalf("expected sum_debits_cents 3000, got %d", page.Aggregates.SumDebitsCents)
+	}
+	if page.Aggregates.DistinctCounterparties != 2 {
+		t.Fatalf("expected 2 distinct counterparties, got %d", page.Aggregates.DistinctCounterparties)
+	}
+	if page.NextCursor != "" {
+		t.Fatalf("expected no next cursor within a single page, got %q", page.NextCursor)
+	}
+
+	firstPage, err := s.Activity(ctx, store.ActivityFilter{AccountIDs: []uuid.UUID{alice}, Limit: 1})
+	if err != nil {
+		t.Fatalf("Activity (page 1): %v", err)
+	}
+	if len(firstPage.Entries) != 1 || firstPage.NextCursor == "" {
+		t.Fatalf("expected 1 entry and a next cursor, got %d entries, cursor %q", len(firstPage.Entries), firstPage.NextCursor)
+	}
+
+	secondPage, err := s.Activity(ctx, store.ActivityFilter{AccountIDs: []uuid.UUID{alice}, Limit: 1, Cursor: firstPage.NextCursor})
+	if err != nil {
+		t.Fatalf("Activity (page 2): %v", err)
+	}
+	if len(secondPage.Entries) != 1 {
+		t.Fatalf("expected 1 entry on page 2, got %d", len(secondPage.Entries))
+	}
+	if secondPage.Entries[0].EntryID == firstPage.Entries[0].EntryID {
+		t.Fatal("expected page 2 to return a different entry than page 1")
+	}
+
+	_, err = s.Activity(ctx, store.ActivityFilter{Currencies: []string{"EUR"}})
+	if err == nil {
+		t.Fatal("expected a filter with no account_ids to be rejected")
+	}
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is an optional sidecar file (invoice, receipt, remittance
+// advice) carried alongside a transfer or journal posting. Attachments are
+// stored in ledger_tx_attachment keyed by tx_id, but deliberately don't
+// participate in TransferIdemShape/JournalIdemShape: replaying the same
+// transfer or journal with different or missing attachments still returns
+// the original tx_id.
+type Attachment struct {
+	MimeType string
+	Filename string
+	SHA256   string
+	Bytes    []byte
+}
+
+// attachmentDigest is what actually goes into a TRANSFER_POSTED/JOURNAL_POSTED
+// event payload: the digest alone, not the bytes, so payload_canonical stays
+// small and the hash chain stays cheap to verify.
+type attachmentDigest struct {
+	MimeType string `json:"mime_type"`
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+func normalizeAttachment(a Attachment) (Attachment, error) {
+	a.MimeType = strings.TrimSpace(a.MimeType)
+	a.Filename = strings.TrimSpace(a.Filename)
+	a.SHA256 = strings.ToLower(strings.TrimSpace(a.SHA256))
+	if a.MimeType == "" || a.Filename == "" || len(a.Bytes) == 0 {
+		return Attachment{}, ErrValidation
+	}
+
+	sum := sha256.Sum256(a.Bytes)
+	want := hex.EncodeToString(sum[:])
+	if a.SHA256 == "" {
+		a.SHA256 = want
+	} else if a.SHA256 != want {
+		return Attachment{}, fmt.Errorf("%w: attachment sha256 mismatch for %q", ErrValidation, a.Filename)
+	}
+
+	return a, nil
+}
+
+// persistAttachments normalizes and writes attachments for txID in the
+// caller's transaction, returning their digests in caller-supplied order for
+// the event payload. It does not touch idempotency state: it's called after
+// the idempotency key has already been reserved/committed for this tx_id.
+func persistAttachments(ctx context.Context, tx pgx.Tx, genUUID func() uuid.UUID, txID uuid.UUID, attachments []Attachment) ([]attachmentDigest, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	digests := make([]attachmentDigest, 0, len(attachments))
+	for _, raw := range attachments {
+		a, err := normalizeAttachment(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO ledger_tx_attachment(attachment_id, tx_id, mime_type, filename, sha256_hex, bytes)
+			VALUES ($1,$2,$3,$4,$5,$6)
+		`, genUUID(), txID, a.MimeType, a.Filename, a.SHA256, a.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		digests = append(digests, attachmentDigest{MimeType: a.MimeType, Filename: a.Filename, SHA256: a.SHA256})
+	}
+	return digests, nil
+}
+
+// TransferAttachments returns every attachment stored against txID (from
+// either PostTransfer or PostJournal), in the order they were inserted.
+func (s *Store) TransferAttachments(ctx context.Context, txID uuid.UUID) ([]Attachment, error) {
+	if txID == uuid.Nil {
+		return nil, ErrValidation
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT mime_type, filename, sha256_hex, bytes
+		  FROM ledger_tx_attachment
+		 WHERE tx_id = $1
+		 ORDER BY created_at ASC
+	`, txID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Attachment
+	for rows.Next() {
+		var a Attachment
+		if err := rows.Scan(&a.MimeType, &a.Filename, &a.SHA256, &a.Bytes); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package store_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestPostTransfer_AttachmentsStoredAndRetrievable(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-attachment-" + uuid.NewString()
+
+	from, err := s.CreateAccount(ctx, "attachment-from-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount from: %v", err)
+	}
+	to, err := s.CreateAccount(ctx, "attachment-to-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount to: %v", err)
+	}
+
+	invoice := []byte("invoice-body")
+	sum := sha256.Sum256(invoice)
+	att := store.Attachment{
+		MimeType: "application/pdf",
+		Filename: "invoice.pdf",
+		SHA256:   hex.EncodeToString(sum[:]),
+		Bytes:    invoice,
+	}
+
+	externalRef := "ext-attachment-" + uuid.NewString()
+	idemKey := "idem-attachment-" + uuid.NewString()
+
+	txID, err := s.PostTransfer(ctx, from, to, 1000, "EUR", externalRef, idemKey, corr, att)
+	if err != nil {
+		t.Fatalf("PostTransfer: %v", err)
+	}
+
+	got, err := s.TransferAttachments(ctx, txID)
+	if err != nil {
+		t.Fatalf("TransferAttachments: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(got))
+	}
+	if got[0].Filename != att.Filename || got[0].MimeType != att.MimeType || got[0].SHA256 != att.SHA256 {
+		t.Fatalf("attachment mismatch: got %+v", got[0])
+	}
+
+	var payload []byte
+	if err := pool.QueryRow(ctx,
+		`SELECT payload_canonical FROM event_log WHERE event_type = 'TRANSFER_POSTED' AND aggregate_id = $1`,
+		txID.String(),
+	).Scan(&payload); err != nil {
+		t.Fatalf("select TRANSFER_POSTED payload: %v", err)
+	}
+	if !strings.Contains(string(payload), att.SHA256) {
+		t.Fatalf("expected payload_canonical to carry the attachment digest, got %s", payload)
+	}
+	if strings.Contains(string(payload), "invoice-body") {
+		t.Fatalf("payload_canonical must not carry attachment bytes, got %s", payload)
+	}
+
+	// Replay with a different (in fact, missing) attachment set: must still
+	// return the original tx_id and must not create a second attachment row.
+	txID2, err := s.PostTransfer(ctx, from, to, 1000, "EUR", externalRef, idemKey, corr)
+	if err != nil {
+		t.Fatalf("PostTransfer (replay): %v", err)
+	}
+	if txID2 != txID {
+		t.Fatalf("expected replay to return the original tx_id: got %s, want %s", txID2, txID)
+	}
+
+	gotAfterReplay, err := s.TransferAttachments(ctx, txID)
+	if err != nil {
+		t.Fatalf("TransferAttachments (after replay): %v", err)
+	}
+	if len(gotAfterReplay) != 1 {
+		t.Fatalf("expected replay to leave attachment count unchanged at 1, got %d", len(gotAfterReplay))
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Attestation is a signed claim over the event_log head at the moment it
+// was built: "as of seq, the chain hash was HeadHash, and the log held this
+// many rows" (DBRunFingerprint), signed by the key named KeyID.
+type Attestation struct {
+	Seq              int64
+	HeadHash         string
+	DBRunFingerprint string
+	KeyID            string
+	Alg              string
+	SignatureHex     string
+	SignedAt         time.Time
+}
+
+// WithAttestationKey registers an Ed25519 key under keyID for SignHead to
+// sign with. Call it once per key the process should accept; later calls
+// add keys rather than replacing the set, so a key can be rotated in without
+// invalidating attestations already signed under an older keyID.
+func WithAttestationKey(keyID string, key ed25519.PrivateKey) Option {
+	return func(s *Store) {
+		if s.attestationKeys == nil {
+			s.attestationKeys = map[string]ed25519.PrivateKey{}
+		}
+		s.attestationKeys[keyID] = key
+	}
+}
+
+// attestationSigningMessage is the exact byte string SignHead signs and
+// cmd/proof-verify re-derives to check a signature: domain-separated and
+// binding every field an auditor would otherwise have to trust out of band.
+func attestationSigningMessage(alg string, seq int64, headHash, dbRunFingerprint string) []byte {
+	return []byte(fmt.Sprintf("ledger-attestation:v1|%s|%d|%s|%s", alg, seq, headHash, dbRunFingerprint))
+}
+
+type headAttestedPayload struct {
+	Seq              int64  `json:"seq"`
+	HeadHash         string `json:"head_hash"`
+	DBRunFingerprint string `json:"db_run_fingerprint"`
+	KeyID            string `json:"key_id"`
+	Alg              string `json:"alg"`
+	SignatureHex     string `json:"signature_hex"`
+}
+
+// SignHead reads the current event_log head under a repeatable-read
+// snapshot, signs it with the Ed25519 key registered under keyID (see
+// WithAttestationKey), and persists the result in event_log_attestation --
+// itself recorded as a HEAD_ATTESTED event_log row in the same transaction,
+// so the act of attesting becomes part of the very chain it attests to.
+func (s *Store) SignHead(ctx context.Context, keyID string) (Attestation, error) {
+	key, ok := s.attestationKeys[keyID]
+	if !ok {
+		return Attestation{}, fmt.Errorf("%w: unknown attestation key %q", ErrValidation, keyID)
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadWrite,
+	})
+	if err != nil {
+		return Attestation{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var seq int64
+	var headHash string
+	err = tx.QueryRow(ctx, `
+		SELECT seq, hash_hex
+		  FROM event_log_proof_export_v
+		 ORDER BY seq DESC
+		 LIMIT 1
+	`).Scan(&seq, &headHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Attestation{}, ErrNotFound
+		}
+		return Attestation{}, err
+	}
+
+	var rowCount int64
+	if err := tx.QueryRow(ctx, `SELECT count(*) FROM event_log`).Scan(&rowCount); err != nil {
+		return Attestation{}, err
+	}
+	fingerprintSum := sha256.Sum256([]byte(fmt.Sprintf("seq=%d|head=%s|count=%d", seq, headHash, rowCount)))
+	fingerprint := hex.EncodeToString(fingerprintSum[:])
+
+	const alg = "ed25519"
+	sig := ed25519.Sign(key, attestationSigningMessage(alg, seq, headHash, fingerprint))
+	sigHex := hex.EncodeToString(sig)
+
+	var signedAt time.Time
+	err = tx.QueryRow(ctx, `
+		INSERT INTO event_log_attestation(seq, head_hash_hex, db_run_fingerprint, key_id, alg, signature_hex)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING signed_at
+	`, seq, headHash, fingerprint, keyID, alg, sigHex).Scan(&signedAt)
+	if err != nil {
+		return Attestation{}, err
+	}
+
+	payload := headAttestedPayload{
+		Seq:              seq,
+		HeadHash:         headHash,
+		DBRunFingerprint: fingerprint,
+		KeyID:            keyID,
+		Alg:              alg,
+		SignatureHex:     sigHex,
+	}
+	if err := insertEvent(ctx, tx, s.newUUID, "HEAD_ATTESTED", "EVENT_LOG", fmt.Sprintf("seq:%d", seq), "system:attestation", payload); err != nil {
+		return Attestation{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Attestation{}, err
+	}
+
+	return Attestation{
+		Seq:              seq,
+		HeadHash:         headHash,
+		DBRunFingerprint: fingerprint,
+		KeyID:            keyID,
+		Alg:              alg,
+		SignatureHex:     sigHex,
+		SignedAt:         signedAt,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package store_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestSignHead_ProducesVerifiableAttestation(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	s := store.New(pool, store.WithAttestationKey("test-key", priv))
+	corr := "t-attestation-" + uuid.NewString()
+
+	if _, err := s.CreateAccount(ctx, "attest-"+uuid.NewString(), "EUR", corr); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	att, err := s.SignHead(ctx, "test-key")
+	if err != nil {
+		t.Fatalf("SignHead: %v", err)
+	}
+	if att.HeadHash == "" || att.DBRunFingerprint == "" || att.SignatureHex == "" {
+		t.Fatalf("SignHead returned an incomplete attestation: %+v", att)
+	}
+
+	var gotHash string
+	if err := pool.QueryRow(ctx, `SELECT COALESCE(hash_hex,'') FROM event_log_proof_export_v ORDER BY seq DESC LIMIT 1`).Scan(&gotHash); err != nil {
+		t.Fatalf("read chain head: %v", err)
+	}
+	if gotHash != att.HeadHash {
+		t.Fatalf("attestation head mismatch: got %s want %s", att.HeadHash, gotHash)
+	}
+
+	sigBytes, err := hex.DecodeString(att.SignatureHex)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	if !ed25519.Verify(pub, attestationMessageForTest(att), sigBytes) {
+		t.Fatal("expected attestation signature to verify")
+	}
+
+	var storedKeyID, storedAlg string
+	if err := pool.QueryRow(ctx, `
+		SELECT key_id, alg FROM event_log_attestation
+		 WHERE seq = $1
+		 ORDER BY signed_at DESC LIMIT 1
+	`, att.Seq).Scan(&storedKeyID, &storedAlg); err != nil {
+		t.Fatalf("read event_log_attestation: %v", err)
+	}
+	if storedKeyID != "test-key" || storedAlg != att.Alg {
+		t.Fatalf("persisted attestation mismatch: key_id=%s alg=%s", storedKeyID, storedAlg)
+	}
+}
+
+// attestationMessageForTest re-derives the exact signing message SignHead
+// uses, the same way cmd/proof-verify does, since the two live in different
+// packages and the message format isn't exported.
+func attestationMessageForTest(att store.Attestation) []byte {
+	return []byte(fmt.Sprintf("ledger-attestation:v1|%s|%d|%s|%s", att.Alg, att.Seq, att.HeadHash, att.DBRunFingerprint))
+}
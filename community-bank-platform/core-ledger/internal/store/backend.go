@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Backend is the ledger-core surface that callers (today, httpapi.Handlers)
+// depend on. *Store is the Postgres-backed implementation; memstore.Store is
+// a pure-Go implementation with the same observable semantics for tests and
+// simulations that don't want a real database, in the spirit of
+// go-ethereum's accounts/abi/bind/backends.SimulatedBackend.
+type Backend interface {
+	CreateAccount(ctx context.Context, label, currency, correlationID string) (uuid.UUID, error)
+
+	PostTransfer(
+		ctx context.Context,
+		fromAcc, toAcc uuid.UUID,
+		amountCents int64,
+		currency, externalRef, idemKey, correlationID string,
+		attachments ...Attachment,
+	) (uuid.UUID, error)
+
+	Balance(ctx context.Context, accountID uuid.UUID) (currency string, balanceCents int64, err error)
+
+	// VerifyEventChain reports whether the append-only event log's hash
+	// chain is intact end to end.
+	VerifyEventChain(ctx context.Context) (bool, error)
+
+	// ChainHead returns the most recent event_log seq/hash_hex pair. It's
+	// what internal/cluster's threshold co-signing signs over: the real,
+	// content-addressed ledger head, the same value an external auditor
+	// checks via cmd/proof-verify, rather than anything internal to Raft
+	// log replication.
+	ChainHead(ctx context.Context) (seq int64, hashHex string, err error)
+}
+
+var _ Backend = (*Store)(nil)
+
+// VerifyEventChain runs the DB-side chain walk used by the concurrency and
+// tamper tests, exposed as a Store method so callers can depend on the
+// Backend interface instead of reaching for raw SQL.
+func (s *Store) VerifyEventChain(ctx context.Context) (bool, error) {
+	var ok bool
+	err := s.db.QueryRow(ctx, `SELECT verify_event_chain()`).Scan(&ok)
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// ChainHead returns the event_log head the same way SignedChainTip does,
+// minus the signature -- a caller that just wants the current (seq,
+// hash_hex) pair, with no checkpoint signing key required.
+func (s *Store) ChainHead(ctx context.Context) (int64, string, error) {
+	var seq int64
+	var hashHex string
+	err := s.db.QueryRow(ctx, `
+		SELECT seq, hash_hex
+		  FROM event_log_proof_export_v
+		 ORDER BY seq DESC
+		 LIMIT 1
+	`).Scan(&seq, &hashHex)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, "", ErrNotFound
+		}
+		return 0, "", err
+	}
+	return seq, hashHex, nil
+}
@@ -0,0 +1,35 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+	"core-ledger/internal/store/backendtest"
+)
+
+// TestStore_BackendConformance runs the same shared table that memstore runs
+// against the real Postgres-backed Store, so the two implementations can't
+// silently drift apart.
+func TestStore_BackendConformance(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	backendtest.Run(t, ctx, store.New(pool), "pgstore")
+}
@@ -0,0 +1,130 @@
+// Package backendtest is a shared conformance table for anything
+// implementing store.Backend. Both *store.Store (Postgres) and
+// memstore.Store (pure Go) are run through the exact same checks so the two
+// implementations can't silently drift apart.
+package backendtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/store"
+)
+
+// Run exercises b against the shared behavioral table. corrPrefix namespaces
+// correlation IDs; labels, idempotency keys and external refs each carry a
+// fresh UUID, so the same suite can run twice against a reused database
+// without colliding on unique constraints.
+func Run(t *testing.T, ctx context.Context, b store.Backend, corrPrefix string) {
+	t.Helper()
+
+	t.Run("double_entry_and_idempotent_replay", func(t *testing.T) {
+		corr := corrPrefix + "-double-entry-" + uuid.NewString()
+
+		sys, err := b.CreateAccount(ctx, "SYSTEM-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(sys): %v", err)
+		}
+		alice, err := b.CreateAccount(ctx, "Alice-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(alice): %v", err)
+		}
+		bob, err := b.CreateAccount(ctx, "Bob-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(bob): %v", err)
+		}
+
+		mintIdem := "idem-mint-" + uuid.NewString()
+		if _, err := b.PostTransfer(ctx, sys, alice, 10000, "EUR", "mint-"+mintIdem, mintIdem, corr); err != nil {
+			t.Fatalf("mint: %v", err)
+		}
+
+		idem := "idem-pmt-" + uuid.NewString()
+		ext := "pmt-" + idem
+		tx1, err := b.PostTransfer(ctx, alice, bob, 2500, "EUR", ext, idem, corr)
+		if err != nil {
+			t.Fatalf("PostTransfer(1): %v", err)
+		}
+		tx2, err := b.PostTransfer(ctx, alice, bob, 2500, "EUR", ext, idem, corr)
+		if err != nil {
+			t.Fatalf("PostTransfer(replay): %v", err)
+		}
+		if tx1 != tx2 {
+			t.Fatalf("replay returned a different tx_id: %s vs %s", tx1, tx2)
+		}
+
+		if _, bal, err := b.Balance(ctx, alice); err != nil || bal != 7500 {
+			t.Fatalf("alice balance: got (%d, %v) want (7500, nil)", bal, err)
+		}
+		if _, bal, err := b.Balance(ctx, bob); err != nil || bal != 2500 {
+			t.Fatalf("bob balance: got (%d, %v) want (2500, nil)", bal, err)
+		}
+
+		ok, err := b.VerifyEventChain(ctx)
+		if err != nil {
+			t.Fatalf("VerifyEventChain: %v", err)
+		}
+		if !ok {
+			t.Fatal("VerifyEventChain: chain broken")
+		}
+	})
+
+	t.Run("idempotency_conflict_on_divergent_replay", func(t *testing.T) {
+		corr := corrPrefix + "-idem-conflict-" + uuid.NewString()
+
+		alice, err := b.CreateAccount(ctx, "Alice-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(alice): %v", err)
+		}
+		bob, err := b.CreateAccount(ctx, "Bob-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(bob): %v", err)
+		}
+		sys, err := b.CreateAccount(ctx, "SYSTEM-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(sys): %v", err)
+		}
+		mintIdem := "idem-mint-" + uuid.NewString()
+		if _, err := b.PostTransfer(ctx, sys, alice, 10000, "EUR", "mint-"+mintIdem, mintIdem, corr); err != nil {
+			t.Fatalf("mint: %v", err)
+		}
+
+		idem := "idem-" + uuid.NewString()
+		if _, err := b.PostTransfer(ctx, alice, bob, 100, "EUR", "ext-a-"+idem, idem, corr); err != nil {
+			t.Fatalf("PostTransfer(1): %v", err)
+		}
+		// Same key, different amount: must conflict, not replay.
+		_, err = b.PostTransfer(ctx, alice, bob, 200, "EUR", "ext-b-"+idem, idem, corr)
+		if !errors.Is(err, store.ErrIdempotencyConflict) {
+			t.Fatalf("got err=%v, want ErrIdempotencyConflict", err)
+		}
+	})
+
+	t.Run("currency_mismatch_rejected", func(t *testing.T) {
+		corr := corrPrefix + "-currency-mismatch-" + uuid.NewString()
+
+		eur, err := b.CreateAccount(ctx, "EUR-"+uuid.NewString(), "EUR", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(eur): %v", err)
+		}
+		usd, err := b.CreateAccount(ctx, "USD-"+uuid.NewString(), "USD", corr)
+		if err != nil {
+			t.Fatalf("CreateAccount(usd): %v", err)
+		}
+
+		idem := "idem-" + uuid.NewString()
+		_, err = b.PostTransfer(ctx, eur, usd, 100, "EUR", "ext-"+idem, idem, corr)
+		if !errors.Is(err, store.ErrValidation) {
+			t.Fatalf("got err=%v, want ErrValidation", err)
+		}
+	})
+
+	t.Run("unknown_account_not_found", func(t *testing.T) {
+		if _, _, err := b.Balance(ctx, uuid.New()); !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("got err=%v, want ErrNotFound", err)
+		}
+	})
+}
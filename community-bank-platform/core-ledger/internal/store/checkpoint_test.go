@@ -0,0 +1,95 @@
+package store_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+	"core-ledger/pkg/eventproof"
+)
+
+func TestCheckpoint_BuildAndProve_RoundTrips(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	s := store.New(pool, store.WithCheckpointSigningKey(priv))
+	corr := "t-checkpoint-" + uuid.NewString()
+
+	var firstSeq, lastSeq int64
+	if err := pool.QueryRow(ctx, `SELECT COALESCE(max(seq),0)+1 FROM event_log`).Scan(&firstSeq); err != nil {
+		t.Fatalf("read starting seq: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.CreateAccount(ctx, "ckpt-"+uuid.NewString(), "EUR", corr); err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+
+	if err := pool.QueryRow(ctx, `SELECT max(seq) FROM event_log`).Scan(&lastSeq); err != nil {
+		t.Fatalf("read ending seq: %v", err)
+	}
+
+	cp, err := s.BuildCheckpoint(ctx, firstSeq, lastSeq)
+	if err != nil {
+		t.Fatalf("BuildCheckpoint: %v", err)
+	}
+	if cp.SignatureHex == "" {
+		t.Fatal("expected a signature on the checkpoint")
+	}
+
+	latest, err := s.LatestCheckpoint(ctx)
+	if err != nil {
+		t.Fatalf("LatestCheckpoint: %v", err)
+	}
+	if latest.RootHex != cp.RootHex {
+		t.Fatalf("latest checkpoint root mismatch: got %s want %s", latest.RootHex, cp.RootHex)
+	}
+
+	for seq := firstSeq; seq <= lastSeq; seq++ {
+		proof, err := s.EventProofBySeq(ctx, seq)
+		if err != nil {
+			t.Fatalf("EventProofBySeq(%d): %v", seq, err)
+		}
+		ok, err := eventproof.Verify(proof, cp.RootHex)
+		if err != nil {
+			t.Fatalf("Verify(%d): %v", seq, err)
+		}
+		if !ok {
+			t.Fatalf("Verify(%d): expected inclusion to hold", seq)
+		}
+	}
+
+	sigBytes, err := hex.DecodeString(cp.SignatureHex)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	if !eventproof.VerifyRootSignature(pub, sigBytes, cp.RootHex, uint64(cp.SeqLo), uint64(cp.SeqHi)) {
+		t.Fatal("expected checkpoint signature to verify")
+	}
+}
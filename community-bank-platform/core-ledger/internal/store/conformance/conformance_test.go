@@ -0,0 +1,57 @@
+package conformance_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store/conformance"
+)
+
+func TestConformance_StarterCorpus(t *testing.T) {
+	if conformance.Skip() {
+		t.Skip("SKIP_CONFORMANCE=1")
+	}
+
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := conformance.VerifyManifest("testdata/vectors"); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+
+	vectors, err := conformance.LoadDir("testdata/vectors")
+	if err != nil {
+		t.Fatalf("load vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("expected at least one starter vector")
+	}
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			res, err := conformance.Run(ctx, pool, v)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			for _, f := range res.Failures {
+				t.Errorf("%s", f)
+			}
+		})
+	}
+}
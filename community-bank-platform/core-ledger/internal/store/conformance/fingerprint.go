@@ -0,0 +1,34 @@
+package conformance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// dbRunFingerprint collapses a vector's observed final state -- every seeded
+// account's balance, the event_log row count, and the chain head hash --
+// into one sha256, so a vector's expect block (or a downstream verifier in
+// another language) can pin a single value instead of three. balances is
+// keyed by the vector-local account name so the fingerprint is independent
+// of the deterministic-UUID scheme used to run it.
+func dbRunFingerprint(balances map[string]AccountBalance, eventCount int, headHash string) string {
+	names := make([]string, 0, len(balances))
+	for name := range balances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		bal := balances[name]
+		fmt.Fprintf(&b, "%s=%s:%d\n", name, bal.Currency, bal.BalanceCents)
+	}
+	fmt.Fprintf(&b, "event_count=%d\n", eventCount)
+	fmt.Fprintf(&b, "head=%s\n", headHash)
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
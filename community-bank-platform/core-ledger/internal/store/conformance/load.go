@@ -0,0 +1,143 @@
+package conformance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Skip reports whether the conformance suite has been opted out of via
+// SKIP_CONFORMANCE=1, matching the env-gated skip convention already used by
+// the real-data scenario tests (LEDGER_DB_DSN).
+func Skip() bool {
+	return os.Getenv("SKIP_CONFORMANCE") == "1"
+}
+
+// manifestFile is the pointer file's reserved name within a vector
+// directory: it describes the corpus but is not itself a Vector, so LoadDir
+// skips it.
+const manifestFile = "manifest.json"
+
+// Manifest is the pointer file committed alongside a vector corpus
+// (testdata/vectors/manifest.json) so an external project -- including a
+// verifier written in another language -- can fetch exactly this corpus and
+// confirm it has the bit-identical files before replaying them.
+type Manifest struct {
+	Schema        int              `json:"schema"`
+	Corpus        string           `json:"corpus"`
+	CorpusVersion int              `json:"corpus_version"`
+	Description   string           `json:"description,omitempty"`
+	Vectors       []ManifestVector `json:"vectors"`
+}
+
+// ManifestVector pins one corpus file to its sha256, hex-encoded.
+type ManifestVector struct {
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// LoadManifest reads and parses dir's manifest.json.
+func LoadManifest(dir string) (Manifest, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("conformance: read manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return Manifest{}, fmt.Errorf("conformance: parse manifest: %w", err)
+	}
+	return m, nil
+}
+
+// VerifyManifest checks that every file the manifest under dir lists is
+// present and has the recorded sha256, and that no extra vector files exist
+// that the manifest doesn't know about. It does not itself load or replay
+// any vector.
+func VerifyManifest(dir string) error {
+	m, err := LoadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	want := make(map[string]string, len(m.Vectors))
+	for _, v := range m.Vectors {
+		want[v.File] = v.SHA256
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("conformance: read dir %s: %w", dir, err)
+	}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" || e.Name() == manifestFile {
+			continue
+		}
+		seen[e.Name()] = true
+		wantSum, ok := want[e.Name()]
+		if !ok {
+			return fmt.Errorf("conformance: %s is not listed in %s", e.Name(), manifestFile)
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("conformance: read %s: %w", e.Name(), err)
+		}
+		sum := sha256.Sum256(raw)
+		if got := hex.EncodeToString(sum[:]); got != wantSum {
+			return fmt.Errorf("conformance: %s: sha256 mismatch: got %s want %s", e.Name(), got, wantSum)
+		}
+	}
+	for name := range want {
+		if !seen[name] {
+			return fmt.Errorf("conformance: %s is listed in %s but missing from %s", name, manifestFile, dir)
+		}
+	}
+	return nil
+}
+
+// LoadDir parses every *.json file directly under dir as a Vector, except
+// manifest.json, sorted by filename for a stable run order.
+func LoadDir(dir string) ([]Vector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("conformance: read dir %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" && e.Name() != manifestFile {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	vectors := make([]Vector, 0, len(names))
+	for _, name := range names {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("conformance: read %s: %w", name, err)
+		}
+		v, err := Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("conformance: %s: %w", name, err)
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// WriteObserved marshals an observed Expect block back over the vector file
+// at path, for `--record` mode: the rest of the vector (accounts, script) is
+// left untouched.
+func WriteObserved(path string, v Vector) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("conformance: marshal %s: %w", path, err)
+	}
+	b = append(b, '\n')
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,310 @@
+package conformance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+// conformanceUUIDNamespace roots the per-vector deterministic UUID generator.
+// Any UUID v5 derived from (namespace, vector name, counter) is stable across
+// runs and across machines, which is what lets a vector pin an exact `tx_id`
+// in its `expect` block.
+var conformanceUUIDNamespace = uuid.MustParse("7b2fd6b0-6e2d-4ad2-9d2a-7b9b9b6a9c10")
+
+// Result is the outcome of replaying a single vector.
+type Result struct {
+	Vector             string
+	StepsRun           int
+	Failures           []string
+	HeadHash           string
+	EventCount         int
+	EventPayloadHashes []string
+	DBRunFingerprint   string
+}
+
+func (r Result) OK() bool { return len(r.Failures) == 0 }
+
+// deterministicUUIDGen returns a store.Option-compatible generator that
+// produces a fresh, reproducible UUID on every call, seeded from vecName.
+func deterministicUUIDGen(vecName string) func() uuid.UUID {
+	n := 0
+	return func() uuid.UUID {
+		n++
+		seed := fmt.Sprintf("%s#%d", vecName, n)
+		return uuid.NewSHA1(conformanceUUIDNamespace, []byte(seed))
+	}
+}
+
+// Run replays v against a migrated schema reachable through pool and diffs
+// actual vs. expected at every step and at the end. It never mutates v.
+func Run(ctx context.Context, pool *pgxpool.Pool, v Vector) (Result, error) {
+	res := Result{Vector: v.Name}
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		return res, fmt.Errorf("conformance: migrate: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if v.Now != "" {
+		parsed, err := time.Parse(time.RFC3339, v.Now)
+		if err != nil {
+			return res, fmt.Errorf("conformance: bad now %q: %w", v.Now, err)
+		}
+		now = parsed
+	}
+
+	st := store.New(pool,
+		store.WithClock(func() time.Time { return now }),
+		store.WithUUIDGen(deterministicUUIDGen(v.Name)),
+	)
+
+	accounts := map[string]uuid.UUID{}
+	txRefs := map[string]uuid.UUID{}
+
+	for _, seed := range v.Accounts {
+		id, err := st.CreateAccount(ctx, seed.Label, seed.Currency, "conformance:"+v.Name)
+		if err != nil {
+			return res, fmt.Errorf("conformance: seed account %q: %w", seed.ID, err)
+		}
+		accounts[seed.ID] = id
+	}
+
+	for i, step := range v.Script {
+		res.StepsRun++
+		if err := runStep(ctx, st, accounts, txRefs, step); err != nil {
+			res.Failures = append(res.Failures, fmt.Sprintf("step %d (%s): %v", i, step.Op, err))
+		}
+	}
+
+	var eventCount int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM event_log`).Scan(&eventCount); err != nil {
+		return res, fmt.Errorf("conformance: count event_log: %w", err)
+	}
+	res.EventCount = eventCount
+	if v.Expect.EventLogLength > 0 && eventCount != v.Expect.EventLogLength {
+		res.Failures = append(res.Failures, fmt.Sprintf("event_log length: got %d want %d", eventCount, v.Expect.EventLogLength))
+	}
+
+	var head string
+	err := pool.QueryRow(ctx, `SELECT COALESCE(hash_hex, '') FROM event_log_proof_export_v ORDER BY seq DESC LIMIT 1`).Scan(&head)
+	if err != nil {
+		return res, fmt.Errorf("conformance: read chain head: %w", err)
+	}
+	res.HeadHash = head
+	if v.Expect.ChainHeadHash != "" && head != v.Expect.ChainHeadHash {
+		res.Failures = append(res.Failures, fmt.Sprintf("chain head hash: got %s want %s", head, v.Expect.ChainHeadHash))
+	}
+
+	payloadHashes, err := eventPayloadHashes(ctx, pool)
+	if err != nil {
+		return res, fmt.Errorf("conformance: read event payload hashes: %w", err)
+	}
+	res.EventPayloadHashes = payloadHashes
+	if len(v.Expect.EventPayloadHashes) > 0 && !equalStrings(payloadHashes, v.Expect.EventPayloadHashes) {
+		res.Failures = append(res.Failures, fmt.Sprintf(
+			"event payload hashes: got %v want %v", payloadHashes, v.Expect.EventPayloadHashes,
+		))
+	}
+
+	observed := make(map[string]AccountBalance, len(accounts))
+	for name, id := range accounts {
+		cur, cents, err := st.Balance(ctx, id)
+		if err != nil {
+			res.Failures = append(res.Failures, fmt.Sprintf("balance(%s): %v", name, err))
+			continue
+		}
+		observed[name] = AccountBalance{Currency: cur, BalanceCents: cents}
+	}
+
+	for name, want := range v.Expect.Balances {
+		got, ok := observed[name]
+		if !ok {
+			res.Failures = append(res.Failures, fmt.Sprintf("expect.balances: unknown account %q", name))
+			continue
+		}
+		if got.Currency != want.Currency || got.BalanceCents != want.BalanceCents {
+			res.Failures = append(res.Failures, fmt.Sprintf(
+				"balance(%s): got %s %d want %s %d", name, got.Currency, got.BalanceCents, want.Currency, want.BalanceCents,
+			))
+		}
+	}
+
+	res.DBRunFingerprint = dbRunFingerprint(observed, eventCount, head)
+	if v.Expect.DBRunFingerprint != "" && res.DBRunFingerprint != v.Expect.DBRunFingerprint {
+		res.Failures = append(res.Failures, fmt.Sprintf(
+			"db_run_fingerprint: got %s want %s", res.DBRunFingerprint, v.Expect.DBRunFingerprint,
+		))
+	}
+
+	return res, nil
+}
+
+// eventPayloadHashes returns sha256(payload_canonical) in hex for every
+// event_log row, in seq order: the byte-level pin a refactor of jcsPayload
+// or insertEvent has to keep matching, independent of the chain hash (which
+// also covers prev_hash_hex and could stay stable even if a payload's bytes
+// quietly changed).
+func eventPayloadHashes(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	rows, err := pool.Query(ctx, `SELECT payload_canonical FROM event_log ORDER BY seq ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var canon string
+		if err := rows.Scan(&canon); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(canon))
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+	}
+	return hashes, rows.Err()
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func runStep(ctx context.Context, st *store.Store, accounts, txRefs map[string]uuid.UUID, step Step) error {
+	switch step.Op {
+	case "create_account":
+		id, err := st.CreateAccount(ctx, step.Label, step.Currency, step.CorrelationID)
+		if err := checkErrClass(err, step.Expect.ErrClass); err != nil {
+			return err
+		}
+		if err == nil && step.AccountID != "" {
+			accounts[step.AccountID] = id
+		}
+		return nil
+
+	case "post_transfer":
+		from, ok := accounts[step.From]
+		if !ok {
+			return fmt.Errorf("unknown account ref %q", step.From)
+		}
+		to, ok := accounts[step.To]
+		if !ok {
+			return fmt.Errorf("unknown account ref %q", step.To)
+		}
+		txID, err := st.PostTransfer(ctx, from, to, step.AmountCents, step.Currency, step.ExternalRef, step.IdempotencyKey, step.CorrelationID)
+		if cerr := checkErrClass(err, step.Expect.ErrClass); cerr != nil {
+			return cerr
+		}
+		if err != nil {
+			return nil
+		}
+		if step.Expect.TxIDRef != "" {
+			if prior, seen := txRefs[step.Expect.TxIDRef]; seen && prior != txID {
+				return fmt.Errorf("tx_id_ref %q: got %s, previously %s", step.Expect.TxIDRef, txID, prior)
+			}
+			txRefs[step.Expect.TxIDRef] = txID
+		}
+		return nil
+
+	case "post_journal":
+		legs := make([]store.JournalLeg, len(step.Legs))
+		for i, leg := range step.Legs {
+			acc, ok := accounts[leg.Account]
+			if !ok {
+				return fmt.Errorf("unknown account ref %q", leg.Account)
+			}
+			legs[i] = store.JournalLeg{AccountID: acc, Direction: leg.Direction, AmountCents: leg.AmountCents}
+		}
+		txID, err := st.PostJournal(ctx, legs, step.Currency, step.ExternalRef, step.IdempotencyKey, step.CorrelationID)
+		if cerr := checkErrClass(err, step.Expect.ErrClass); cerr != nil {
+			return cerr
+		}
+		if err != nil {
+			return nil
+		}
+		if step.Expect.TxIDRef != "" {
+			if prior, seen := txRefs[step.Expect.TxIDRef]; seen && prior != txID {
+				return fmt.Errorf("tx_id_ref %q: got %s, previously %s", step.Expect.TxIDRef, txID, prior)
+			}
+			txRefs[step.Expect.TxIDRef] = txID
+		}
+		return nil
+
+	case "balance":
+		id, ok := accounts[step.Account]
+		if !ok {
+			return fmt.Errorf("unknown account ref %q", step.Account)
+		}
+		cur, cents, err := st.Balance(ctx, id)
+		if cerr := checkErrClass(err, step.Expect.ErrClass); cerr != nil {
+			return cerr
+		}
+		if err != nil {
+			return nil
+		}
+		if step.Expect.BalanceCents != nil && cents != *step.Expect.BalanceCents {
+			return fmt.Errorf("balance: got %d want %d", cents, *step.Expect.BalanceCents)
+		}
+		if step.Expect.Currency != "" && cur != step.Expect.Currency {
+			return fmt.Errorf("currency: got %s want %s", cur, step.Expect.Currency)
+		}
+		return nil
+
+	case "verify_chain":
+		// Delegated to the caller's final chain-head assertion; present so
+		// vectors can document *where* in the script a chain check matters.
+		return nil
+
+	default:
+		return fmt.Errorf("unknown op %q", step.Op)
+	}
+}
+
+// checkErrClass reconciles a returned error against a vector's expected
+// error class name. wantClass == "" means "no error".
+func checkErrClass(err error, wantClass string) error {
+	if wantClass == "" {
+		if err != nil {
+			return fmt.Errorf("unexpected error: %w", err)
+		}
+		return nil
+	}
+	if wantClass == "Any" {
+		if err == nil {
+			return fmt.Errorf("expected an error, got none")
+		}
+		return nil
+	}
+	want, ok := errClasses[wantClass]
+	if !ok {
+		return fmt.Errorf("vector references unknown err_class %q", wantClass)
+	}
+	if !errors.Is(err, want) {
+		return fmt.Errorf("got error %v, want class %s", err, wantClass)
+	}
+	return nil
+}
+
+// errClasses maps a vector's `err_class` string to the sentinel it must
+// match via errors.Is. "Any" (handled above) means "some error, unclassified"
+// -- used for DB-enforced invariants (e.g. insufficient funds) that surface
+// as a raw driver error rather than one of the Go-level sentinels.
+var errClasses = map[string]error{
+	"ErrValidation":          store.ErrValidation,
+	"ErrNotFound":            store.ErrNotFound,
+	"ErrIdempotencyConflict": store.ErrIdempotencyConflict,
+}
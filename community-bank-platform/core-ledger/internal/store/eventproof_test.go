@@ -0,0 +1,125 @@
+package store_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestEventProof_VerifiesChainLinkageToTip(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-eventproof-" + uuid.NewString()
+
+	if _, err := s.CreateAccount(ctx, "eventproof-a-"+uuid.NewString(), "EUR", corr); err != nil {
+		t.Fatalf("CreateAccount a: %v", err)
+	}
+
+	var eventID uuid.UUID
+	if err := pool.QueryRow(ctx,
+		`SELECT event_id FROM event_log WHERE aggregate_type='ACCOUNT' ORDER BY seq ASC LIMIT 1`,
+	).Scan(&eventID); err != nil {
+		t.Fatalf("read earliest event_id: %v", err)
+	}
+
+	// Post more events after the one we're proving, so the chain to the tip is non-trivial.
+	if _, err := s.CreateAccount(ctx, "eventproof-b-"+uuid.NewString(), "EUR", corr); err != nil {
+		t.Fatalf("CreateAccount b: %v", err)
+	}
+
+	proof, err := s.EventProof(ctx, eventID)
+	if err != nil {
+		t.Fatalf("EventProof: %v", err)
+	}
+	if proof.ThisHash == "" || proof.ChainTipHash == "" || len(proof.Siblings) < 1 {
+		t.Fatalf("EventProof returned an incomplete proof: %+v", proof)
+	}
+
+	if err := store.VerifyEventProof(proof); err != nil {
+		t.Fatalf("VerifyEventProof: %v", err)
+	}
+
+	tampered := proof
+	tampered.Siblings = append([]store.HashLink(nil), proof.Siblings...)
+	tampered.Siblings[len(tampered.Siblings)-1].HashHex = "00"
+	if err := store.VerifyEventProof(tampered); err == nil {
+		t.Fatal("expected VerifyEventProof to reject a tampered chain tip")
+	}
+
+	// Mirrors TestEventChain_TamperByDisablingTriggers_FailsVerification in
+	// concurrency_test.go: payload_canonical changed in place, hash_hex and
+	// payload_hash_hex left untouched.
+	tamperedPayload := proof
+	tamperedPayload.PayloadCanonical = `{"tampered":true}`
+	if err := store.VerifyEventProof(tamperedPayload); err == nil {
+		t.Fatal("expected VerifyEventProof to reject a tampered payload_canonical")
+	}
+}
+
+func TestSignedChainTip_ProducesVerifiableSignature(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	s := store.New(pool, store.WithCheckpointSigningKey(priv))
+	corr := "t-chaintip-" + uuid.NewString()
+
+	if _, err := s.CreateAccount(ctx, "chaintip-"+uuid.NewString(), "EUR", corr); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	tip, err := s.SignedChainTip(ctx)
+	if err != nil {
+		t.Fatalf("SignedChainTip: %v", err)
+	}
+	if tip.HeadHash == "" || tip.SignatureHex == "" {
+		t.Fatalf("SignedChainTip returned an incomplete tip: %+v", tip)
+	}
+	if !store.VerifySignedChainTip(pub, tip) {
+		t.Fatal("expected chain tip signature to verify")
+	}
+
+	tip.Seq++
+	if store.VerifySignedChainTip(pub, tip) {
+		t.Fatal("expected chain tip signature to fail after the seq is altered")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"core-ledger/internal/store"
+	"core-ledger/pkg/canonjson"
 )
 
 type phase struct {
@@ -156,8 +157,8 @@ func insertValuation(
 ) error {
 	t.Helper()
 
-	jcs := mustJCS(t, payload)
-	h := riskPayloadHashValuation(assetType, assetID, asOf, price, currency, source, confidence, jcs)
+	jcs := mustCanonicalize(t, payload)
+	h := canonjson.NewValuationHash(assetType, assetID, asOf, price, currency, source, confidence, jcs)
 
 	_, err := pool.Exec(ctx, `
 		INSERT INTO valuation_snapshot(
@@ -191,8 +192,8 @@ func insertLiquidity(
 ) error {
 	t.Helper()
 
-	jcs := mustJCS(t, payload)
-	h := riskPayloadHashLiquidity(assetType, assetID, asOf, haircutBps, ttcSeconds, source, jcs)
+	jcs := mustCanonicalize(t, payload)
+	h := canonjson.NewLiquidityHash(assetType, assetID, asOf, haircutBps, ttcSeconds, source, jcs)
 
 	_, err := pool.Exec(ctx, `
 		INSERT INTO liquidity_snapshot(
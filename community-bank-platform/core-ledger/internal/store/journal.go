@@ -0,0 +1,331 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"core-ledger/pkg/canonjson"
+)
+
+// JournalLeg is one side of a multi-leg journal posting: PostJournal accepts
+// 2..N of these instead of PostTransfer's fixed from/to pair, so a single
+// compound business event (a fee, a tax split, an FX leg, a multi-party
+// settlement) can be written as one ledger_tx rather than several linked
+// transfers.
+type JournalLeg struct {
+	AccountID   uuid.UUID
+	Direction   string // "DEBIT" or "CREDIT"
+	AmountCents int64
+}
+
+// JournalLegShape is a JournalLeg's canonical, deterministic form for
+// idempotency hashing.
+type JournalLegShape struct {
+	AccountID   string `json:"account_id"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// JournalIdemShape is the canonical, deterministic request shape for
+// journal idempotency hashing. Legs are sorted by (direction, account_id,
+// amount_cents) before hashing, same as TransferIdemShape's single-leg
+// shape: the business meaning of a journal posting doesn't depend on the
+// order legs were supplied in, so two requests differing only in leg order
+// must hash identically.
+type JournalIdemShape struct {
+	Legs           []JournalLegShape `json:"legs"`
+	Currency       string            `json:"currency"`
+	ExternalRef    string            `json:"external_ref"`
+	IdempotencyKey string            `json:"idempotency_key"`
+	CorrelationID  string            `json:"correlation_id"`
+}
+
+func hashJournalIdem(shape JournalIdemShape) (string, error) {
+	b, err := json.Marshal(shape)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+func normalizeDirection(dir string) (string, error) {
+	dir = strings.ToUpper(strings.TrimSpace(dir))
+	if dir != "DEBIT" && dir != "CREDIT" {
+		return "", ErrValidation
+	}
+	return dir, nil
+}
+
+func buildJournalIdemShape(
+	legs []JournalLeg,
+	currency, externalRef, idemKey, correlationID string,
+) (JournalIdemShape, error) {
+	if len(legs) < 2 {
+		return JournalIdemShape{}, ErrValidation
+	}
+
+	externalRef = strings.TrimSpace(externalRef)
+	idemKey = strings.TrimSpace(idemKey)
+	correlationID = strings.TrimSpace(correlationID)
+	if externalRef == "" || idemKey == "" || correlationID == "" {
+		return JournalIdemShape{}, ErrValidation
+	}
+
+	cur, err := normalizeCurrency(currency)
+	if err != nil {
+		return JournalIdemShape{}, err
+	}
+
+	var debitTotal, creditTotal int64
+	legShapes := make([]JournalLegShape, len(legs))
+	for i, leg := range legs {
+		if leg.AccountID == uuid.Nil || leg.AmountCents <= 0 {
+			return JournalIdemShape{}, ErrValidation
+		}
+		dir, err := normalizeDirection(leg.Direction)
+		if err != nil {
+			return JournalIdemShape{}, err
+		}
+		switch dir {
+		case "DEBIT":
+			debitTotal += leg.AmountCents
+		case "CREDIT":
+			creditTotal += leg.AmountCents
+		}
+		legShapes[i] = JournalLegShape{
+			AccountID:   leg.AccountID.String(),
+			Direction:   dir,
+			AmountCents: leg.AmountCents,
+		}
+	}
+	if debitTotal != creditTotal {
+		return JournalIdemShape{}, fmt.Errorf("%w: unbalanced journal: debits=%d credits=%d", ErrValidation, debitTotal, creditTotal)
+	}
+
+	sort.Slice(legShapes, func(i, j int) bool {
+		a, b := legShapes[i], legShapes[j]
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.AmountCents < b.AmountCents
+	})
+
+	return JournalIdemShape{
+		Legs:           legShapes,
+		Currency:       cur,
+		ExternalRef:    externalRef,
+		IdempotencyKey: idemKey,
+		CorrelationID:  correlationID,
+	}, nil
+}
+
+// journalLegRow is one leg as passed to post_balanced_journal's p_legs
+// jsonb argument -- it carries the entry_id the Go layer generated, which
+// the canonical idempotency shape deliberately omits (entry IDs are
+// internal bookkeeping, not part of what makes two requests "the same").
+type journalLegRow struct {
+	EntryID     string `json:"entry_id"`
+	AccountID   string `json:"account_id"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type journalPostedLegPayload struct {
+	AccountID   string `json:"account_id"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type journalPostedPayload struct {
+	TxID        string                    `json:"tx_id"`
+	Legs        []journalPostedLegPayload `json:"legs"`
+	Currency    string                    `json:"currency"`
+	ExternalRef string                    `json:"external_ref"`
+	Idempotency string                    `json:"idempotency"`
+	Attachments []attachmentDigest        `json:"attachments,omitempty"`
+}
+
+// JournalResponse is the canonical, minimal, stable response stored in
+// idempotency.response_json for a journal posting.
+type JournalResponse struct {
+	TxID string `json:"tx_id"`
+}
+
+// PostJournal posts a balanced, 2..N-leg journal entry atomically: one
+// ledger_tx and one ledger_entry per leg, plus a single JOURNAL_POSTED event
+// carrying every leg so downstream consumers see the compound event at once
+// rather than reconstructing it from several TRANSFER_POSTED events.
+// Sum(debits) must equal sum(credits) in currency; this is enforced here,
+// in Go, before post_balanced_journal ever runs, so a caller gets
+// ErrValidation instead of a constraint violation from the DB.
+// attachments behave exactly as in PostTransfer: optional, stored against
+// the resulting tx_id, and excluded from the idempotency hash.
+func (s *Store) PostJournal(
+	ctx context.Context,
+	legs []JournalLeg,
+	currency, externalRef, idemKey, correlationID string,
+	attachments ...Attachment,
+) (uuid.UUID, error) {
+	shape, err := buildJournalIdemShape(legs, currency, externalRef, idemKey, correlationID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	requestHash, err := hashJournalIdem(shape)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize per idempotency key, same as PostTransfer.
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, shape.IdempotencyKey)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO idempotency(key, request_hash, status)
+		 VALUES($1,$2,'RESERVED')
+		 ON CONFLICT (key) DO NOTHING`,
+		shape.IdempotencyKey, requestHash,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		var existingHash string
+		var existingTx *uuid.UUID
+
+		err := tx.QueryRow(ctx,
+			`SELECT request_hash, tx_id FROM idempotency WHERE key=$1`,
+			shape.IdempotencyKey,
+		).Scan(&existingHash, &existingTx)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if existingHash != requestHash {
+			return uuid.Nil, ErrIdempotencyConflict
+		}
+		if existingTx == nil {
+			return uuid.Nil, fmt.Errorf("%w: idempotency reserved without tx_id", ErrValidation)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return uuid.Nil, err
+		}
+		return *existingTx, nil
+	}
+
+	// Ensure every leg's account exists and matches the journal's currency
+	// (DB is authoritative; same check PostTransfer runs per side).
+	for _, leg := range legs {
+		var cur string
+		err = tx.QueryRow(ctx, `SELECT currency FROM accounts WHERE account_id=$1`, leg.AccountID).Scan(&cur)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, ErrNotFound
+			}
+			return uuid.Nil, err
+		}
+		if cur != shape.Currency {
+			return uuid.Nil, fmt.Errorf("%w: currency mismatch", ErrValidation)
+		}
+	}
+
+	txID := s.newUUID()
+	legRows := make([]journalLegRow, len(legs))
+	eventLegs := make([]journalPostedLegPayload, len(legs))
+	for i, leg := range legs {
+		dir, _ := normalizeDirection(leg.Direction) // already validated in buildJournalIdemShape
+		legRows[i] = journalLegRow{
+			EntryID:     s.newUUID().String(),
+			AccountID:   leg.AccountID.String(),
+			Direction:   dir,
+			AmountCents: leg.AmountCents,
+		}
+		eventLegs[i] = journalPostedLegPayload{
+			AccountID:   leg.AccountID.String(),
+			Direction:   dir,
+			AmountCents: leg.AmountCents,
+		}
+	}
+	legsJSON, err := json.Marshal(legRows)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Canonical DB posting: creates ledger_tx + one ledger_entry per leg atomically.
+	_, err = tx.Exec(ctx, `
+		SELECT post_balanced_journal($1,$2,$3,$4,$5,$6::jsonb)
+	`,
+		txID,
+		shape.ExternalRef,
+		shape.CorrelationID,
+		shape.IdempotencyKey,
+		shape.Currency,
+		legsJSON,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	resp := JournalResponse{TxID: txID.String()}
+	respCanonBytes, err := canonjson.Canonicalize(resp)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	responseJSON := JSONBytes(respCanonBytes)
+
+	var committedTx uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT tx_id FROM idem_commit($1,$2,$3::jsonb)`,
+		shape.IdempotencyKey, txID, responseJSON,
+	).Scan(&committedTx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	txID = committedTx
+
+	digests, err := persistAttachments(ctx, tx, s.newUUID, txID, attachments)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	evPayload := journalPostedPayload{
+		TxID:        txID.String(),
+		Legs:        eventLegs,
+		Currency:    shape.Currency,
+		ExternalRef: shape.ExternalRef,
+		Idempotency: shape.IdempotencyKey,
+		Attachments: digests,
+	}
+	if err := insertEvent(ctx, tx, s.newUUID, "JOURNAL_POSTED", "LEDGER_TX", txID.String(), shape.CorrelationID, evPayload); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	return txID, nil
+}
@@ -0,0 +1,132 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestPostJournal_BalancedMultiLeg(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-journal-" + uuid.NewString()
+
+	source, err := s.CreateAccount(ctx, "journal-source-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount source: %v", err)
+	}
+	fee, err := s.CreateAccount(ctx, "journal-fee-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount fee: %v", err)
+	}
+	dest, err := s.CreateAccount(ctx, "journal-dest-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount dest: %v", err)
+	}
+
+	legsAsc := []store.JournalLeg{
+		{AccountID: source, Direction: "CREDIT", AmountCents: 10000},
+		{AccountID: fee, Direction: "DEBIT", AmountCents: 500},
+		{AccountID: dest, Direction: "DEBIT", AmountCents: 9500},
+	}
+	// Same legs, different order: must hash identically and replay, not conflict.
+	legsDesc := []store.JournalLeg{legsAsc[2], legsAsc[0], legsAsc[1]}
+
+	externalRef := "ext-journal-" + uuid.NewString()
+	idemKey := "idem-journal-" + uuid.NewString()
+
+	txID1, err := s.PostJournal(ctx, legsAsc, "EUR", externalRef, idemKey, corr)
+	if err != nil {
+		t.Fatalf("PostJournal (first): %v", err)
+	}
+
+	txID2, err := s.PostJournal(ctx, legsDesc, "EUR", externalRef, idemKey, corr)
+	if err != nil {
+		t.Fatalf("PostJournal (reordered legs, same key): %v", err)
+	}
+	if txID1 != txID2 {
+		t.Fatalf("expected leg-order-independent replay to return the same tx_id: got %s and %s", txID1, txID2)
+	}
+
+	var evCount int
+	if err := pool.QueryRow(ctx,
+		`SELECT count(*) FROM event_log WHERE event_type = 'JOURNAL_POSTED' AND aggregate_id = $1`,
+		txID1.String(),
+	).Scan(&evCount); err != nil {
+		t.Fatalf("count JOURNAL_POSTED events: %v", err)
+	}
+	if evCount != 1 {
+		t.Fatalf("expected exactly 1 JOURNAL_POSTED event, got %d", evCount)
+	}
+
+	var entryCount int
+	if err := pool.QueryRow(ctx,
+		`SELECT count(*) FROM ledger_entry WHERE tx_id = $1`,
+		txID1,
+	).Scan(&entryCount); err != nil {
+		t.Fatalf("count ledger_entry rows: %v", err)
+	}
+	if entryCount != len(legsAsc) {
+		t.Fatalf("expected %d ledger_entry rows, got %d", len(legsAsc), entryCount)
+	}
+}
+
+func TestPostJournal_UnbalancedRejected(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-journal-unbalanced-" + uuid.NewString()
+
+	a, err := s.CreateAccount(ctx, "journal-a-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount a: %v", err)
+	}
+	b, err := s.CreateAccount(ctx, "journal-b-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount b: %v", err)
+	}
+
+	legs := []store.JournalLeg{
+		{AccountID: a, Direction: "CREDIT", AmountCents: 10000},
+		{AccountID: b, Direction: "DEBIT", AmountCents: 9000},
+	}
+
+	_, err = s.PostJournal(ctx, legs, "EUR", "ext-"+uuid.NewString(), "idem-"+uuid.NewString(), corr)
+	if err == nil {
+		t.Fatal("expected unbalanced journal to be rejected")
+	}
+}
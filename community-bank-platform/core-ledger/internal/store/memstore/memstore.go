@@ -0,0 +1,331 @@
+// Package memstore is a pure-Go, no-Postgres implementation of
+// store.Backend, in the spirit of go-ethereum's
+// accounts/abi/bind/backends.SimulatedBackend: it reproduces the observable
+// semantics of *store.Store (transfer atomicity, idempotency replay,
+// hash-chained append-only event log) without a database, so the HTTP layer
+// and other callers can be unit-tested without LEDGER_DB_DSN.
+//
+// It is not a performance backend: a single mutex serializes every
+// operation. That is deliberate -- the whole point is a small, obviously
+// correct reference implementation to test against, not a second production
+// ledger engine.
+package memstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/store"
+)
+
+type account struct {
+	id       uuid.UUID
+	label    string
+	currency string
+	credits  int64
+	debits   int64
+}
+
+type attachmentDigest struct {
+	MimeType string `json:"mime_type"`
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+type idemEntry struct {
+	shape store.TransferIdemShape
+	txID  uuid.UUID
+}
+
+type event struct {
+	seq       int64
+	eventType string
+	payload   string // JSON of a map payload; json.Marshal sorts map keys, so the bytes are stable
+	prevHash  string
+	hash      string
+}
+
+// Store is a pure-Go store.Backend.
+type Store struct {
+	mu sync.Mutex
+
+	newUUID func() uuid.UUID
+
+	accounts    map[uuid.UUID]*account
+	idem        map[string]*idemEntry
+	events      []event
+	nextSeq     int64
+	attachments map[uuid.UUID][]store.Attachment
+}
+
+// Option customizes a Store at construction time, mirroring store.Option.
+type Option func(*Store)
+
+// WithUUIDGen overrides the store's UUID generator, e.g. so multiple
+// replicas of internal/cluster's FSM derive byte-identical ids for the same
+// replicated log entry instead of each rolling its own random UUID.
+func WithUUIDGen(gen func() uuid.UUID) Option {
+	return func(s *Store) { s.newUUID = gen }
+}
+
+// New returns an empty, ready-to-use in-memory backend.
+func New(opts ...Option) *Store {
+	s := &Store{
+		newUUID:     uuid.New,
+		accounts:    map[uuid.UUID]*account{},
+		idem:        map[string]*idemEntry{},
+		nextSeq:     1,
+		attachments: map[uuid.UUID][]store.Attachment{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ store.Backend = (*Store)(nil)
+
+func normalizeCurrency(cur string) (string, error) {
+	cur = strings.ToUpper(strings.TrimSpace(cur))
+	if len(cur) != 3 {
+		return "", store.ErrValidation
+	}
+	return cur, nil
+}
+
+func normalizeAttachment(a store.Attachment) (store.Attachment, error) {
+	a.MimeType = strings.TrimSpace(a.MimeType)
+	a.Filename = strings.TrimSpace(a.Filename)
+	a.SHA256 = strings.ToLower(strings.TrimSpace(a.SHA256))
+	if a.MimeType == "" || a.Filename == "" || len(a.Bytes) == 0 {
+		return store.Attachment{}, store.ErrValidation
+	}
+
+	sum := sha256.Sum256(a.Bytes)
+	want := hex.EncodeToString(sum[:])
+	if a.SHA256 == "" {
+		a.SHA256 = want
+	} else if a.SHA256 != want {
+		return store.Attachment{}, errors.Join(store.ErrValidation, errors.New("attachment sha256 mismatch"))
+	}
+	return a, nil
+}
+
+func (s *Store) appendEventLocked(eventType string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	prevHash := ""
+	if n := len(s.events); n > 0 {
+		prevHash = s.events[n-1].hash
+	}
+
+	h := sha256.Sum256([]byte(prevHash + string(b)))
+	s.events = append(s.events, event{
+		seq:       s.nextSeq,
+		eventType: eventType,
+		payload:   string(b),
+		prevHash:  prevHash,
+		hash:      hex.EncodeToString(h[:]),
+	})
+	s.nextSeq++
+	return nil
+}
+
+func (s *Store) CreateAccount(_ context.Context, label, currency, correlationID string) (uuid.UUID, error) {
+	label = strings.TrimSpace(label)
+	if label == "" || strings.TrimSpace(correlationID) == "" {
+		return uuid.Nil, store.ErrValidation
+	}
+	cur, err := normalizeCurrency(currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newUUID()
+	s.accounts[id] = &account{id: id, label: label, currency: cur}
+
+	if err := s.appendEventLocked("ACCOUNT_CREATED", map[string]string{
+		"account_id": id.String(),
+		"label":      label,
+		"currency":   cur,
+	}); err != nil {
+		delete(s.accounts, id)
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (s *Store) PostTransfer(
+	_ context.Context,
+	fromAcc, toAcc uuid.UUID,
+	amountCents int64,
+	currency, externalRef, idemKey, correlationID string,
+	attachments ...store.Attachment,
+) (uuid.UUID, error) {
+	if fromAcc == uuid.Nil || toAcc == uuid.Nil || fromAcc == toAcc {
+		return uuid.Nil, store.ErrValidation
+	}
+	if amountCents <= 0 {
+		return uuid.Nil, store.ErrValidation
+	}
+	externalRef = strings.TrimSpace(externalRef)
+	idemKey = strings.TrimSpace(idemKey)
+	correlationID = strings.TrimSpace(correlationID)
+	if externalRef == "" || idemKey == "" || correlationID == "" {
+		return uuid.Nil, store.ErrValidation
+	}
+	cur, err := normalizeCurrency(currency)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	shape := store.TransferIdemShape{
+		FromAccountID:  fromAcc.String(),
+		ToAccountID:    toAcc.String(),
+		AmountCents:    amountCents,
+		Currency:       cur,
+		ExternalRef:    externalRef,
+		IdempotencyKey: idemKey,
+		CorrelationID:  correlationID,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Idempotency state machine: RESERVED -> COMMITTED. The first caller
+	// for a key commits; every later caller with the identical shape
+	// replays the same tx_id, and any caller with a divergent shape gets
+	// ErrIdempotencyConflict -- same contract as Store.PostTransfer.
+	if existing, ok := s.idem[idemKey]; ok {
+		if existing.shape != shape {
+			return uuid.Nil, store.ErrIdempotencyConflict
+		}
+		return existing.txID, nil
+	}
+
+	from, ok := s.accounts[fromAcc]
+	if !ok {
+		return uuid.Nil, store.ErrNotFound
+	}
+	to, ok := s.accounts[toAcc]
+	if !ok {
+		return uuid.Nil, store.ErrNotFound
+	}
+	if from.currency != cur || to.currency != cur {
+		return uuid.Nil, errors.Join(store.ErrValidation, errors.New("currency mismatch"))
+	}
+
+	txID := s.newUUID()
+
+	var digests []attachmentDigest
+	normalized := make([]store.Attachment, 0, len(attachments))
+	for _, raw := range attachments {
+		a, err := normalizeAttachment(raw)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		normalized = append(normalized, a)
+		digests = append(digests, attachmentDigest{MimeType: a.MimeType, Filename: a.Filename, SHA256: a.SHA256})
+	}
+
+	from.debits += amountCents
+	to.credits += amountCents
+
+	payload := map[string]any{
+		"tx_id":        txID.String(),
+		"from":         fromAcc.String(),
+		"to":           toAcc.String(),
+		"amount_cents": amountCents,
+		"currency":     cur,
+		"external_ref": externalRef,
+		"idempotency":  idemKey,
+	}
+	if len(digests) > 0 {
+		payload["attachments"] = digests
+	}
+
+	if err := s.appendEventLocked("TRANSFER_POSTED", payload); err != nil {
+		from.debits -= amountCents
+		to.credits -= amountCents
+		return uuid.Nil, err
+	}
+	if len(normalized) > 0 {
+		s.attachments[txID] = normalized
+	}
+
+	s.idem[idemKey] = &idemEntry{shape: shape, txID: txID}
+	return txID, nil
+}
+
+func (s *Store) Balance(_ context.Context, accountID uuid.UUID) (string, int64, error) {
+	if accountID == uuid.Nil {
+		return "", 0, store.ErrValidation
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[accountID]
+	if !ok {
+		return "", 0, store.ErrNotFound
+	}
+	return a.currency, a.credits - a.debits, nil
+}
+
+// TransferAttachments returns the attachments stored against txID, mirroring
+// store.Store.TransferAttachments. Not part of the Backend interface, same
+// as store.Store's method.
+func (s *Store) TransferAttachments(_ context.Context, txID uuid.UUID) ([]store.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]store.Attachment(nil), s.attachments[txID]...), nil
+}
+
+func (s *Store) VerifyEventChain(_ context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prevHash := ""
+	for i, ev := range s.events {
+		if ev.seq != int64(i+1) {
+			return false, nil
+		}
+		if ev.prevHash != prevHash {
+			return false, nil
+		}
+		h := sha256.Sum256([]byte(prevHash + ev.payload))
+		if hex.EncodeToString(h[:]) != ev.hash {
+			return false, nil
+		}
+		prevHash = ev.hash
+	}
+	return true, nil
+}
+
+// ChainHead returns the seq/hash of the most recently appended event, the
+// same pair store.Store.ChainHead reads off event_log_proof_export_v.
+func (s *Store) ChainHead(_ context.Context) (int64, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) == 0 {
+		return 0, "", store.ErrNotFound
+	}
+	last := s.events[len(s.events)-1]
+	return last.seq, last.hash, nil
+}
@@ -0,0 +1,13 @@
+package memstore_test
+
+import (
+	"context"
+	"testing"
+
+	"core-ledger/internal/store/backendtest"
+	"core-ledger/internal/store/memstore"
+)
+
+func TestMemstore_BackendConformance(t *testing.T) {
+	backendtest.Run(t, context.Background(), memstore.New(), "memstore")
+}
@@ -2,38 +2,394 @@ package store
 
 import (
 	"context"
+	"crypto/sha256"
 	"embed"
 	"fmt"
+	"os"
+	"regexp"
 	"sort"
-	"strings"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
-func Migrate(ctx context.Context, db *pgxpool.Pool) error {
+// migrationsAdvisoryLockKey is an arbitrary fixed key every core-ledger
+// process takes via pg_advisory_lock before touching schema_migrations, so
+// concurrent pods starting up at once serialize instead of racing to apply
+// the same version twice. Its only required property is that it's stable
+// across the fleet; the value is just "core-ldg" read as bytes.
+const migrationsAdvisoryLockKey int64 = 0x636f72652d6c6467
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// migration is one versioned step, parsed from a NNNN_name.up.sql /
+// NNNN_name.down.sql pair embedded under migrations/.
+type migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum [32]byte // sha256 of UpSQL; what schema_migrations.checksum pins
+}
+
+// loadMigrations parses every embedded migrations/*.sql file into ordered,
+// paired migrations. It fails if a version is missing either half of its
+// up/down pair, or if two files claim the same version under different
+// names -- both indicate a corrupt migrations directory, not something to
+// paper over at runtime.
+func loadMigrations() ([]migration, error) {
 	entries, err := migrationsFS.ReadDir("migrations")
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	type halves struct {
+		name     string
+		up, down *string
 	}
-	var files []string
+	byVersion := map[int64]*halves{}
+
 	for _, e := range entries {
-		if strings.HasSuffix(e.Name(), ".sql") {
-			files = append(files, "migrations/"+e.Name())
+		mm := migrationFilePattern.FindStringSubmatch(e.Name())
+		if mm == nil {
+			return nil, fmt.Errorf("store: migrations/%s does not match NNNN_name.(up|down).sql", e.Name())
 		}
-	}
-	sort.Strings(files)
+		version, err := strconv.ParseInt(mm[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("store: migrations/%s: bad version: %w", e.Name(), err)
+		}
+		name, direction := mm[2], mm[3]
 
-	for _, f := range files {
-		sqlBytes, err := migrationsFS.ReadFile(f)
+		raw, err := migrationsFS.ReadFile("migrations/" + e.Name())
 		if err != nil {
-			return err
+			return nil, err
+		}
+		content := string(raw)
+
+		h, ok := byVersion[version]
+		if !ok {
+			h = &halves{name: name}
+			byVersion[version] = h
+		} else if h.name != name {
+			return nil, fmt.Errorf("store: migration version %d has conflicting names %q and %q", version, h.name, name)
+		}
+
+		switch direction {
+		case "up":
+			h.up = &content
+		case "down":
+			h.down = &content
+		}
+	}
+
+	versions := make([]int64, 0, len(byVersion))
+	for v := range byVersion {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	out := make([]migration, 0, len(versions))
+	for _, v := range versions {
+		h := byVersion[v]
+		if h.up == nil {
+			return nil, fmt.Errorf("store: migration %04d_%s is missing its .up.sql file", v, h.name)
 		}
-		if _, err := db.Exec(ctx, string(sqlBytes)); err != nil {
-			return fmt.Errorf("migration %s failed: %w", f, err)
+		if h.down == nil {
+			return nil, fmt.Errorf("store: migration %04d_%s is missing its .down.sql file", v, h.name)
+		}
+		out = append(out, migration{
+			Version:  v,
+			Name:     h.name,
+			UpSQL:    *h.up,
+			DownSQL:  *h.down,
+			Checksum: sha256.Sum256([]byte(*h.up)),
+		})
+	}
+	return out, nil
+}
+
+// dbConn is the subset of *pgxpool.Pool / *pgxpool.Conn the migration
+// runner needs, so it can run either directly against the pool (read-only
+// paths) or against a single advisory-locked connection (the apply path).
+type dbConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+func ensureSchemaMigrationsTable(ctx context.Context, db dbConn) error {
+	_, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			checksum   BYTEA NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			applied_by TEXT NOT NULL
+		)
+	`)
+	return err
+}
+
+type appliedRecord struct {
+	Name      string
+	Checksum  []byte
+	AppliedAt time.Time
+}
+
+func appliedMigrations(ctx context.Context, db dbConn) (map[int64]appliedRecord, error) {
+	rows, err := db.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64]appliedRecord{}
+	for rows.Next() {
+		var version int64
+		var rec appliedRecord
+		if err := rows.Scan(&version, &rec.Name, &rec.Checksum, &rec.AppliedAt); err != nil {
+			return nil, err
+		}
+		out[version] = rec
+	}
+	return out, rows.Err()
+}
+
+// appliedBy identifies who/what ran a migration, recorded for audit
+// purposes alongside the checksum.
+func appliedBy() string {
+	h, err := os.Hostname()
+	if err != nil || h == "" {
+		return "unknown"
+	}
+	return h
+}
+
+func applyUp(ctx context.Context, db dbConn, m migration) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.UpSQL); err != nil {
+		return fmt.Errorf("store: migration %04d_%s up: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO schema_migrations(version, name, checksum, applied_by)
+		VALUES ($1,$2,$3,$4)
+	`, m.Version, m.Name, m.Checksum[:], appliedBy()); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func applyDown(ctx context.Context, db dbConn, m migration) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.DownSQL); err != nil {
+		return fmt.Errorf("store: migration %04d_%s down: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Migrate brings the schema to the latest embedded migration. It is a thin
+// wrapper over MigrateTo kept for the many callers (cmd/server,
+// cmd/ledger-cluster, the test suite) that only ever want "latest".
+func Migrate(ctx context.Context, db *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	return MigrateTo(ctx, db, migrations[len(migrations)-1].Version)
+}
+
+// MigrateTo brings the schema to exactly targetVersion: pending .up.sql
+// files run in order if targetVersion is ahead of the recorded state,
+// .down.sql files run in reverse order if it's behind. targetVersion 0
+// means "no migrations applied". A Postgres advisory lock is held for the
+// duration so concurrent processes starting up at once serialize instead
+// of racing to apply the same version twice. Every already-applied
+// migration's checksum is compared against the embedded file; a mismatch
+// fails loudly rather than silently re-running drifted SQL.
+func MigrateTo(ctx context.Context, db *pgxpool.Pool, targetVersion int64) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if targetVersion != 0 {
+		found := false
+		for _, m := range migrations {
+			if m.Version == targetVersion {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: unknown migration version %d", ErrValidation, targetVersion)
+		}
+	}
+
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationsAdvisoryLockKey); err != nil {
+		return fmt.Errorf("store: acquire migration lock: %w", err)
+	}
+	defer conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrationsAdvisoryLockKey)
+
+	if err := ensureSchemaMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	var currentVersion int64
+	for _, m := range migrations {
+		rec, ok := applied[m.Version]
+		if !ok {
+			continue
+		}
+		if string(rec.Checksum) != string(m.Checksum[:]) {
+			return fmt.Errorf("store: migration %04d_%s checksum mismatch: the DB recorded a different file than what's embedded now", m.Version, m.Name)
+		}
+		if m.Version > currentVersion {
+			currentVersion = m.Version
+		}
+	}
+
+	switch {
+	case targetVersion > currentVersion:
+		for _, m := range migrations {
+			if m.Version <= currentVersion || m.Version > targetVersion {
+				continue
+			}
+			if err := applyUp(ctx, conn, m); err != nil {
+				return err
+			}
+		}
+	case targetVersion < currentVersion:
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if m.Version > currentVersion || m.Version <= targetVersion {
+				continue
+			}
+			if err := applyDown(ctx, conn, m); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
+
+// MigrationStatus is one migration's position relative to the DB, as
+// reported by MigrateStatus.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// MigrateStatus reports every embedded migration and whether/when it has
+// been applied. Unlike MigrateTo it never takes the advisory lock or
+// changes anything -- safe to run against a live DB at any time.
+func MigrateStatus(ctx context.Context, db *pgxpool.Pool) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSchemaMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if rec, ok := applied[m.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = rec.AppliedAt
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// ForceVersion stamps schema_migrations to record every migration up to and
+// including version as applied -- and nothing above it -- without running
+// any SQL. It exists for the same reason golang-migrate/goose's "force"
+// does: recovering the bookkeeping table after an operator has manually
+// fixed a dirty DB, so the next MigrateTo doesn't try to re-run or skip the
+// wrong thing.
+func ForceVersion(ctx context.Context, db *pgxpool.Pool, version int64) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	if version != 0 {
+		found := false
+		for _, m := range migrations {
+			if m.Version == version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: unknown migration version %d", ErrValidation, version)
+		}
+	}
+
+	if err := ensureSchemaMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.Version > version {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO schema_migrations(version, name, checksum, applied_by)
+			VALUES ($1,$2,$3,$4)
+			ON CONFLICT (version) DO UPDATE SET checksum = EXCLUDED.checksum
+		`, m.Version, m.Name, m.Checksum[:], appliedBy()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,40 @@
+package store
+
+import "testing"
+
+// TestLoadMigrations_EmbeddedCorpusIsWellFormed checks the invariants
+// loadMigrations enforces -- paired up/down files, strictly increasing
+// versions, stable names -- against whatever is actually embedded, with no
+// DB required.
+func TestLoadMigrations_EmbeddedCorpusIsWellFormed(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected at least one migration")
+	}
+
+	var prev int64 = -1
+	for _, m := range migrations {
+		if m.Version <= prev {
+			t.Fatalf("migrations out of order: version %d follows %d", m.Version, prev)
+		}
+		prev = m.Version
+
+		if m.Name == "" {
+			t.Fatalf("version %d has an empty name", m.Version)
+		}
+		if m.UpSQL == "" {
+			t.Fatalf("version %d (%s) has empty up SQL", m.Version, m.Name)
+		}
+		if m.DownSQL == "" {
+			t.Fatalf("version %d (%s) has empty down SQL", m.Version, m.Name)
+		}
+
+		var zero [32]byte
+		if m.Checksum == zero {
+			t.Fatalf("version %d (%s) has a zero checksum", m.Version, m.Name)
+		}
+	}
+}
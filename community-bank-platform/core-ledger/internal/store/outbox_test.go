@@ -0,0 +1,67 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+// TestCreateAccount_StagesEventOutboxRow checks that insertEvent's outbox
+// side effect actually lands: every event_log row it writes should have a
+// matching event_outbox row, unpublished, with headers carrying the same
+// seq + hash_hex a downstream consumer would need to re-run the chain check.
+func TestCreateAccount_StagesEventOutboxRow(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-outbox-" + uuid.NewString()
+
+	accID, err := s.CreateAccount(ctx, "outbox-"+uuid.NewString(), "USD", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	var seq int64
+	var hashHex string
+	var publishedAt *string
+	var headersJSON []byte
+	err = pool.QueryRow(ctx, `
+		SELECT eo.seq, eo.hash_hex, eo.published_at, eo.headers_jsonb
+		  FROM event_outbox eo
+		  JOIN event_log el ON el.seq = eo.seq
+		 WHERE el.aggregate_type = 'ACCOUNT' AND el.aggregate_id = $1
+		 ORDER BY eo.seq DESC
+		 LIMIT 1
+	`, accID.String()).Scan(&seq, &hashHex, &publishedAt, &headersJSON)
+	if err != nil {
+		t.Fatalf("read event_outbox: %v", err)
+	}
+	if hashHex == "" {
+		t.Fatal("expected a non-empty hash_hex on the staged outbox row")
+	}
+	if publishedAt != nil {
+		t.Fatal("expected a freshly staged row to be unpublished")
+	}
+	if len(headersJSON) == 0 {
+		t.Fatal("expected non-empty headers_jsonb")
+	}
+}
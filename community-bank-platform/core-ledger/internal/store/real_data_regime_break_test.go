@@ -14,6 +14,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"core-ledger/internal/store"
+	"core-ledger/pkg/canonjson"
 
 	"path/filepath"
 	"runtime"
@@ -160,8 +161,8 @@ func insertValuationRegimeBreak(
 ) error {
 	t.Helper()
 
-	jcs := mustJCS(t, payload)
-	h := riskPayloadHashValuation(assetType, assetID, asOf, price, currency, source, confidence, jcs)
+	jcs := mustCanonicalize(t, payload)
+	h := canonjson.NewValuationHash(assetType, assetID, asOf, price, currency, source, confidence, jcs)
 
 	// payload_json must match canonical content but can be stored as jsonb; we reuse the JCS string.
 	_, err := pool.Exec(ctx, `
@@ -208,8 +209,8 @@ func insertLiquidityRegimeBreak(
 ) error {
 	t.Helper()
 
-	jcs := mustJCS(t, payload)
-	h := riskPayloadHashLiquidity(assetType, assetID, asOf, haircutBps, ttcSeconds, source, jcs)
+	jcs := mustCanonicalize(t, payload)
+	h := canonjson.NewLiquidityHash(assetType, assetID, asOf, haircutBps, ttcSeconds, source, jcs)
 
 	_, err := pool.Exec(ctx, `
 		INSERT INTO liquidity_snapshot(
@@ -11,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"core-ledger/internal/store"
+	"core-ledger/pkg/canonjson"
 )
 
 type RealScenario struct {
@@ -82,8 +83,8 @@ func TestScenario_RealPublicData_Covid2020(t *testing.T) {
 		ingestCorr := "ingest-" + sc.ScenarioID + "-" + p.PhaseID + "-" + asOf.Format("2006-01-02")
 
 		for _, v := range p.Valuations {
-			payloadJCS := mustJCS(t, v.Payload)
-			payloadHash := riskPayloadHashValuation(
+			payloadJCS := mustCanonicalize(t, v.Payload)
+			payloadHash := canonjson.NewValuationHash(
 				v.AssetType, v.AssetID, asOf, v.Price, v.Currency, v.Source, v.Confidence, payloadJCS,
 			)
 
@@ -111,8 +112,8 @@ func TestScenario_RealPublicData_Covid2020(t *testing.T) {
 		}
 
 		for _, l := range p.Liquidities {
-			payloadJCS := mustJCS(t, l.Payload)
-			payloadHash := riskPayloadHashLiquidity(
+			payloadJCS := mustCanonicalize(t, l.Payload)
+			payloadHash := canonjson.NewLiquidityHash(
 				l.AssetType, l.AssetID, asOf, l.HaircutBps, l.TimeToCashSeconds, l.Source, payloadJCS,
 			)
 
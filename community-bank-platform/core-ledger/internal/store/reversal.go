@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"core-ledger/pkg/canonjson"
+)
+
+// transferReversedPayload is the TRANSFER_REVERSED event payload. It names
+// both tx_ids explicitly (rather than relying on aggregate_id alone) so a
+// downstream consumer can follow the causal link without a second lookup.
+type transferReversedPayload struct {
+	OrigTxID        string `json:"orig_tx_id"`
+	ReversalTxID    string `json:"reversal_tx_id"`
+	Reason          string `json:"reason"`
+	OrigExternalRef string `json:"orig_external_ref"`
+}
+
+// TransferLineage is the reversal relationship, if any, a tx_id participates
+// in. A tx can reverse at most one other tx and be reversed by at most one
+// other tx, so this is a pair of optional neighbors rather than a list.
+type TransferLineage struct {
+	TxID           uuid.UUID  `json:"tx_id"`
+	ReversesTxID   *uuid.UUID `json:"reverses_tx_id,omitempty"`
+	ReversedByTxID *uuid.UUID `json:"reversed_by_tx_id,omitempty"`
+}
+
+// ReverseTransfer posts a compensating transfer for origTxID: same currency
+// and amount, debit/credit swapped, atomically linked back to the original
+// via ledger_tx.reversed_by and a TRANSFER_REVERSED event. origTxID must
+// name an existing, not-yet-reversed 2-leg transfer (i.e. something posted
+// by PostTransfer or an earlier ReverseTransfer, not a multi-leg
+// PostJournal tx -- see the leg-count check below).
+//
+// The reversal's idempotency hash incorporates reverses_tx_id (see
+// TransferIdemShape.ReversesTxID), so replaying the same (origTxID, idemKey)
+// pair returns the original reversal's tx_id instead of erroring or
+// double-reversing.
+func (s *Store) ReverseTransfer(ctx context.Context, origTxID uuid.UUID, reason, idemKey, correlationID string) (uuid.UUID, error) {
+	reason = strings.TrimSpace(reason)
+	idemKey = strings.TrimSpace(idemKey)
+	correlationID = strings.TrimSpace(correlationID)
+	if origTxID == uuid.Nil || reason == "" || idemKey == "" || correlationID == "" {
+		return uuid.Nil, ErrValidation
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize per idempotency key, same as PostTransfer/PostJournal.
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, idemKey)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var origExternalRef string
+	var reversedBy *uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT external_ref, reversed_by FROM ledger_tx WHERE tx_id = $1 FOR UPDATE`,
+		origTxID,
+	).Scan(&origExternalRef, &reversedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT account_id, direction, amount_cents, currency FROM ledger_entry WHERE tx_id = $1`,
+		origTxID,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	var debitAcc, creditAcc uuid.UUID
+	var amountCents int64
+	var currency string
+	legCount := 0
+	for rows.Next() {
+		var accID uuid.UUID
+		var direction string
+		var cents int64
+		var cur string
+		if err := rows.Scan(&accID, &direction, &cents, &cur); err != nil {
+			rows.Close()
+			return uuid.Nil, err
+		}
+		legCount++
+		amountCents = cents
+		currency = cur
+		switch direction {
+		case "DEBIT":
+			debitAcc = accID
+		case "CREDIT":
+			creditAcc = accID
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return uuid.Nil, err
+	}
+	rows.Close()
+	if legCount != 2 || debitAcc == uuid.Nil || creditAcc == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("%w: tx %s is not a simple 2-leg transfer", ErrValidation, origTxID)
+	}
+
+	// Compensating posting: debit/credit swapped relative to the original.
+	shape := TransferIdemShape{
+		FromAccountID:  creditAcc.String(),
+		ToAccountID:    debitAcc.String(),
+		AmountCents:    amountCents,
+		Currency:       currency,
+		ExternalRef:    "reversal:" + origTxID.String(),
+		IdempotencyKey: idemKey,
+		CorrelationID:  correlationID,
+		ReversesTxID:   origTxID.String(),
+	}
+	requestHash, err := hashTransferIdem(shape)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO idempotency(key, request_hash, status)
+		 VALUES($1,$2,'RESERVED')
+		 ON CONFLICT (key) DO NOTHING`,
+		shape.IdempotencyKey, requestHash,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		var existingHash string
+		var existingTx *uuid.UUID
+		err := tx.QueryRow(ctx,
+			`SELECT request_hash, tx_id FROM idempotency WHERE key=$1`,
+			shape.IdempotencyKey,
+		).Scan(&existingHash, &existingTx)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if existingHash != requestHash {
+			return uuid.Nil, ErrIdempotencyConflict
+		}
+		if existingTx == nil {
+			return uuid.Nil, fmt.Errorf("%w: idempotency reserved without tx_id", ErrValidation)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return uuid.Nil, err
+		}
+		return *existingTx, nil
+	}
+
+	if reversedBy != nil {
+		return uuid.Nil, fmt.Errorf("%w: tx %s already reversed by %s", ErrValidation, origTxID, *reversedBy)
+	}
+
+	reversalTxID := s.newUUID()
+	debitEntryID := s.newUUID()
+	creditEntryID := s.newUUID()
+
+	_, err = tx.Exec(ctx, `
+		SELECT post_balanced_tx($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	`,
+		reversalTxID,
+		shape.ExternalRef,
+		shape.CorrelationID,
+		shape.IdempotencyKey,
+		creditAcc,
+		debitAcc,
+		amountCents,
+		currency,
+		debitEntryID,
+		creditEntryID,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	resp := TransferResponse{TxID: reversalTxID.String()}
+	respCanonBytes, err := canonjson.Canonicalize(resp)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	responseJSON := JSONBytes(respCanonBytes)
+
+	var committedTx uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT tx_id FROM idem_commit($1,$2,$3::jsonb)`,
+		shape.IdempotencyKey, reversalTxID, responseJSON,
+	).Scan(&committedTx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	reversalTxID = committedTx
+
+	tag, err = tx.Exec(ctx,
+		`UPDATE ledger_tx SET reversed_by = $1 WHERE tx_id = $2 AND reversed_by IS NULL`,
+		reversalTxID, origTxID,
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return uuid.Nil, fmt.Errorf("%w: tx %s already reversed", ErrValidation, origTxID)
+	}
+
+	evPayload := transferReversedPayload{
+		OrigTxID:        origTxID.String(),
+		ReversalTxID:    reversalTxID.String(),
+		Reason:          reason,
+		OrigExternalRef: origExternalRef,
+	}
+	if err := insertEvent(ctx, tx, s.newUUID, "TRANSFER_REVERSED", "LEDGER_TX", reversalTxID.String(), correlationID, evPayload); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	return reversalTxID, nil
+}
+
+// TransferLineage reports the reversal relationship, if any, for txID:
+// whether it reverses another tx, and whether it has itself been reversed.
+func (s *Store) TransferLineage(ctx context.Context, txID uuid.UUID) (TransferLineage, error) {
+	if txID == uuid.Nil {
+		return TransferLineage{}, ErrValidation
+	}
+
+	var reversedBy *uuid.UUID
+	err := s.db.QueryRow(ctx, `SELECT reversed_by FROM ledger_tx WHERE tx_id = $1`, txID).Scan(&reversedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TransferLineage{}, ErrNotFound
+		}
+		return TransferLineage{}, err
+	}
+
+	var reverses *uuid.UUID
+	err = s.db.QueryRow(ctx, `SELECT tx_id FROM ledger_tx WHERE reversed_by = $1`, txID).Scan(&reverses)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return TransferLineage{}, err
+	}
+
+	return TransferLineage{TxID: txID, ReversesTxID: reverses, ReversedByTxID: reversedBy}, nil
+}
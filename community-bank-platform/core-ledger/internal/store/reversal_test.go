@@ -0,0 +1,110 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/internal/store"
+)
+
+func TestReverseTransfer_SwapsDebitCreditAndLinksBack(t *testing.T) {
+	dsn := os.Getenv("LEDGER_DB_DSN")
+	if dsn == "" {
+		t.Skip("LEDGER_DB_DSN is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("pgxpool new: %v", err)
+	}
+	defer pool.Close()
+
+	if err := store.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := store.New(pool)
+	corr := "t-reversal-" + uuid.NewString()
+
+	alice, err := s.CreateAccount(ctx, "reversal-alice-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount alice: %v", err)
+	}
+	bob, err := s.CreateAccount(ctx, "reversal-bob-"+uuid.NewString(), "EUR", corr)
+	if err != nil {
+		t.Fatalf("CreateAccount bob: %v", err)
+	}
+
+	origTxID, err := s.PostTransfer(ctx, alice, bob, 5000, "EUR", "ext-"+uuid.NewString(), "idem-"+uuid.NewString(), corr)
+	if err != nil {
+		t.Fatalf("PostTransfer: %v", err)
+	}
+
+	idemKey := "idem-reverse-" + uuid.NewString()
+	reversalTxID, err := s.ReverseTransfer(ctx, origTxID, "booked in error", idemKey, corr)
+	if err != nil {
+		t.Fatalf("ReverseTransfer: %v", err)
+	}
+
+	_, aliceCents, err := s.Balance(ctx, alice)
+	if err != nil {
+		t.Fatalf("Balance alice: %v", err)
+	}
+	if aliceCents != 0 {
+		t.Fatalf("expected alice balance restored to 0, got %d", aliceCents)
+	}
+	_, bobCents, err := s.Balance(ctx, bob)
+	if err != nil {
+		t.Fatalf("Balance bob: %v", err)
+	}
+	if bobCents != 0 {
+		t.Fatalf("expected bob balance restored to 0, got %d", bobCents)
+	}
+
+	// Replaying the same reversal request must return the same tx_id, not double-reverse.
+	reversalTxID2, err := s.ReverseTransfer(ctx, origTxID, "booked in error", idemKey, corr)
+	if err != nil {
+		t.Fatalf("ReverseTransfer (replay): %v", err)
+	}
+	if reversalTxID2 != reversalTxID {
+		t.Fatalf("expected replay to return the same tx_id: got %s, want %s", reversalTxID2, reversalTxID)
+	}
+
+	// A second, distinct reversal attempt on the same original tx must be rejected.
+	_, err = s.ReverseTransfer(ctx, origTxID, "booked in error", "idem-reverse-again-"+uuid.NewString(), corr)
+	if err == nil {
+		t.Fatal("expected a second reversal of the same tx to be rejected")
+	}
+
+	lineage, err := s.TransferLineage(ctx, origTxID)
+	if err != nil {
+		t.Fatalf("TransferLineage(orig): %v", err)
+	}
+	if lineage.ReversedByTxID == nil || *lineage.ReversedByTxID != reversalTxID {
+		t.Fatalf("expected orig tx lineage to point at reversal %s, got %+v", reversalTxID, lineage)
+	}
+
+	reversalLineage, err := s.TransferLineage(ctx, reversalTxID)
+	if err != nil {
+		t.Fatalf("TransferLineage(reversal): %v", err)
+	}
+	if reversalLineage.ReversesTxID == nil || *reversalLineage.ReversesTxID != origTxID {
+		t.Fatalf("expected reversal lineage to point back at orig %s, got %+v", origTxID, reversalLineage)
+	}
+
+	var evCount int
+	if err := pool.QueryRow(ctx,
+		`SELECT count(*) FROM event_log WHERE event_type = 'TRANSFER_REVERSED' AND aggregate_id = $1`,
+		reversalTxID.String(),
+	).Scan(&evCount); err != nil {
+		t.Fatalf("count TRANSFER_REVERSED events: %v", err)
+	}
+	if evCount != 1 {
+		t.Fatalf("expected exactly 1 TRANSFER_REVERSED event, got %d", evCount)
+	}
+}
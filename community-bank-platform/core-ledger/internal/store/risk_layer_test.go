@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"core-ledger/pkg/canonjson"
 )
 
 func TestRiskLayer_EventLogProofs_AppendOnly_ChainOK(t *testing.T) {
@@ -25,10 +27,13 @@ func TestRiskLayer_EventLogProofs_AppendOnly_ChainOK(t *testing.T) {
 	ingestCorr := "ingest-" + corr + "-" + asOf.Format("2006-01-02")
 
 	valPayload := map[string]any{"source": "fred", "note": "test"}
-	valPayloadJCS := mustJCS(t, valPayload)
-	valHash := riskPayloadHashValuation("RATE", "FRED:DGS10", asOf, "4.06", "USD", "fred", 90, valPayloadJCS)
+	valPayloadJCS, err := canonjson.Canonicalize(valPayload)
+	if err != nil {
+		t.Fatalf("canonicalize: %v", err)
+	}
+	valHash := canonjson.NewValuationHash("RATE", "FRED:DGS10", asOf, "4.06", "USD", "fred", 90, valPayloadJCS)
 
-	_, err := pool.Exec(ctx, `
+	_, err = pool.Exec(ctx, `
 		INSERT INTO valuation_snapshot(
 			snapshot_id,
 			ingestion_correlation_id,
@@ -64,8 +69,11 @@ func TestRiskLayer_EventLogProofs_AppendOnly_ChainOK(t *testing.T) {
 	}
 
 	liqPayload := map[string]any{"source": "synthetic", "note": "test"}
-	liqPayloadJCS := mustJCS(t, liqPayload)
-	liqHash := riskPayloadHashLiquidity("FX", "ECB:EXR.D.USD.EUR.SP00.A", asOf, 0, 0, "synthetic", liqPayloadJCS)
+	liqPayloadJCS, err := canonjson.Canonicalize(liqPayload)
+	if err != nil {
+		t.Fatalf("canonicalize: %v", err)
+	}
+	liqHash := canonjson.NewLiquidityHash("FX", "ECB:EXR.D.USD.EUR.SP00.A", asOf, 0, 0, "synthetic", liqPayloadJCS)
 
 	_, err = pool.Exec(ctx, `
 		INSERT INTO liquidity_snapshot(
@@ -2,17 +2,20 @@ package store
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/gowebpki/jcs"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"core-ledger/pkg/canonjson"
 )
 
 var (
@@ -22,10 +25,47 @@ var (
 )
 
 type Store struct {
-	db *pgxpool.Pool
+	db              *pgxpool.Pool
+	now             func() time.Time
+	newUUID         func() uuid.UUID
+	signingKey      ed25519.PrivateKey
+	attestationKeys map[string]ed25519.PrivateKey
+}
+
+// Option customizes a Store at construction time. The defaults (wall clock,
+// random UUIDv4) are what production wiring wants; tests and the conformance
+// runner override them so that `tx_id`s and any future timestamp-derived
+// fields are reproducible across runs.
+type Option func(*Store)
+
+// WithClock overrides the store's notion of "now". Used by the conformance
+// runner to pin vectors to a fixed instant.
+func WithClock(now func() time.Time) Option {
+	return func(s *Store) { s.now = now }
 }
 
-func New(db *pgxpool.Pool) *Store { return &Store{db: db} }
+// WithUUIDGen overrides the store's UUID generator. Used by the conformance
+// runner so that `tx_id`s are deterministic and can be pinned in a vector's
+// `expect` block.
+func WithUUIDGen(gen func() uuid.UUID) Option {
+	return func(s *Store) { s.newUUID = gen }
+}
+
+// WithCheckpointSigningKey configures an Ed25519 key used to sign every
+// event-log checkpoint root built by BuildCheckpoint. Without it,
+// checkpoints are still built and verifiable via their Merkle proof, just
+// unsigned.
+func WithCheckpointSigningKey(key ed25519.PrivateKey) Option {
+	return func(s *Store) { s.signingKey = key }
+}
+
+func New(db *pgxpool.Pool, opts ...Option) *Store {
+	s := &Store{db: db, now: time.Now, newUUID: uuid.New}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
 
 // =========================
 // Idempotency canonical shape
@@ -41,6 +81,12 @@ type TransferIdemShape struct {
 	ExternalRef    string `json:"external_ref"`
 	IdempotencyKey string `json:"idempotency_key"`
 	CorrelationID  string `json:"correlation_id"`
+
+	// ReversesTxID is set only by Store.ReverseTransfer, so a reversal's
+	// idempotency hash is distinct from any ordinary transfer that happens
+	// to share the same from/to/amount/currency -- and so replaying the
+	// same reversal request is safe.
+	ReversesTxID string `json:"reverses_tx_id,omitempty"`
 }
 
 func hashTransferIdem(shape TransferIdemShape) (string, error) {
@@ -104,23 +150,43 @@ type JSONBytes = json.RawMessage
 // jcsPayload returns both representations required by the DB schema:
 // - payload_json: regular JSON bytes (to be cast to jsonb in SQL)
 // - payload_canonical: RFC 8785 canonical JSON string (JCS)
+// Canonicalization itself lives in pkg/canonjson, the one place every
+// writer of payload_canonical/payload_hash agrees on JCS bytes.
 func jcsPayload(v any) (payloadJSON JSONBytes, payloadCanonical string, err error) {
 	raw, err := json.Marshal(v)
 	if err != nil {
 		return nil, "", err
 	}
-	canon, err := jcs.Transform(raw)
+	canon, err := canonjson.Canonicalize(v)
 	if err != nil {
 		return nil, "", err
 	}
 	return JSONBytes(raw), string(canon), nil
 }
 
+// outboxHeaders is the headers_jsonb carried alongside every event_outbox
+// row. seq + hash_hex let a downstream consumer re-run the same chain check
+// cmd/proof-verify does, without needing to join back to event_log.
+type outboxHeaders struct {
+	Seq            int64  `json:"seq"`
+	HashHex        string `json:"hash_hex"`
+	PrevHashHex    string `json:"prev_hash_hex"`
+	PayloadHashHex string `json:"payload_hash_hex"`
+	EventType      string `json:"event_type"`
+	AggregateType  string `json:"aggregate_type"`
+	AggregateID    string `json:"aggregate_id"`
+	CorrelationID  string `json:"correlation_id"`
+}
+
 // insertEvent is the single entry point for event_log inserts.
 // It guarantees payload_json (bytes) + payload_canonical (JCS string), matching DB invariants.
+// It also stages the row into event_outbox (see internal/outbox), so
+// cmd/ledger-relay has a durable, claimable queue to publish from without
+// polling event_log directly.
 func insertEvent(
 	ctx context.Context,
 	tx pgx.Tx,
+	genUUID func() uuid.UUID,
 	eventType, aggregateType, aggregateID, correlationID string,
 	payload any,
 ) error {
@@ -136,11 +202,61 @@ func insertEvent(
 		return err
 	}
 
-	_, err = tx.Exec(ctx,
+	var seq int64
+	var hashHex string
+	var prevHashHex *string
+	err = tx.QueryRow(ctx,
 		`INSERT INTO event_log(
 			event_id, event_type, aggregate_type, aggregate_id, correlation_id, payload_json, payload_canonical
-		) VALUES($1,$2,$3,$4,$5,$6::jsonb,$7)`,
-		uuid.New(), eventType, aggregateType, aggregateID, correlationID, payloadJSON, payloadCanonical,
+		) VALUES($1,$2,$3,$4,$5,$6::jsonb,$7)
+		RETURNING seq, hash_hex, prev_hash_hex`,
+		genUUID(), eventType, aggregateType, aggregateID, correlationID, payloadJSON, payloadCanonical,
+	).Scan(&seq, &hashHex, &prevHashHex)
+	if err != nil {
+		return err
+	}
+
+	prevHashHexValue := ""
+	if prevHashHex != nil {
+		prevHashHexValue = *prevHashHex
+	}
+
+	// payload_hash_hex is ours, not the trigger's: it binds payload_canonical
+	// (plus this row's position and identity) into a hash Store.VerifyEventProof
+	// can recompute later without knowing hash_hex's own byte layout. See
+	// migrations/0008_event_log_payload_hash.up.sql.
+	payloadHash := canonjson.NewEventChainPayloadHash(
+		seq, eventType, aggregateType, aggregateID, correlationID, prevHashHexValue, payloadCanonical,
+	)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	if _, err := tx.Exec(ctx,
+		`UPDATE event_log SET payload_hash_hex=$1 WHERE seq=$2`,
+		payloadHashHex, seq,
+	); err != nil {
+		return err
+	}
+
+	headers := outboxHeaders{
+		Seq:            seq,
+		HashHex:        hashHex,
+		PayloadHashHex: payloadHashHex,
+		EventType:      eventType,
+		AggregateType:  aggregateType,
+		AggregateID:    aggregateID,
+		CorrelationID:  correlationID,
+	}
+	if prevHashHex != nil {
+		headers.PrevHashHex = *prevHashHex
+	}
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO event_outbox(seq, aggregate_id, payload_canonical, hash_hex, prev_hash_hex, headers_jsonb)
+		 VALUES($1,$2,$3,$4,$5,$6::jsonb)`,
+		seq, aggregateID, payloadCanonical, hashHex, prevHashHex, headersJSON,
 	)
 	return err
 }
@@ -152,13 +268,14 @@ type accountCreatedPayload struct {
 }
 
 type transferPostedPayload struct {
-	TxID        string `json:"tx_id"`
-	From        string `json:"from"`
-	To          string `json:"to"`
-	AmountCents int64  `json:"amount_cents"`
-	Currency    string `json:"currency"`
-	ExternalRef string `json:"external_ref"`
-	Idempotency string `json:"idempotency"`
+	TxID        string             `json:"tx_id"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	AmountCents int64              `json:"amount_cents"`
+	Currency    string             `json:"currency"`
+	ExternalRef string             `json:"external_ref"`
+	Idempotency string             `json:"idempotency"`
+	Attachments []attachmentDigest `json:"attachments,omitempty"`
 }
 
 // TransferResponse is the canonical, minimal, stable response stored in idempotency.response_json.
@@ -176,7 +293,7 @@ func (s *Store) CreateAccount(ctx context.Context, label, currency, correlationI
 		return uuid.Nil, err
 	}
 
-	accID := uuid.New()
+	accID := s.newUUID()
 
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel:   pgx.ReadCommitted,
@@ -200,7 +317,7 @@ func (s *Store) CreateAccount(ctx context.Context, label, currency, correlationI
 		Label:     label,
 		Currency:  cur,
 	}
-	if err := insertEvent(ctx, tx, "ACCOUNT_CREATED", "ACCOUNT", accID.String(), correlationID, payload); err != nil {
+	if err := insertEvent(ctx, tx, s.newUUID, "ACCOUNT_CREATED", "ACCOUNT", accID.String(), correlationID, payload); err != nil {
 		return uuid.Nil, err
 	}
 
@@ -213,11 +330,16 @@ func (s *Store) CreateAccount(ctx context.Context, label, currency, correlationI
 // PostTransfer posts a single balanced transaction (1 debit, 1 credit) with strict idempotency.
 // DB enforces accounting invariants and append-only behavior.
 // Contract: external_ref and idempotency_key are unique (DB constraints).
+// attachments are optional sidecar files (see Attachment) stored against the
+// resulting tx_id; they do not participate in the idempotency hash, so
+// replaying the same transfer with different or missing attachments still
+// returns the original tx_id.
 func (s *Store) PostTransfer(
 	ctx context.Context,
 	fromAcc, toAcc uuid.UUID,
 	amountCents int64,
 	currency, externalRef, idemKey, correlationID string,
+	attachments ...Attachment,
 ) (uuid.UUID, error) {
 	shape, err := buildTransferIdemShape(fromAcc, toAcc, amountCents, currency, externalRef, idemKey, correlationID)
 	if err != nil {
@@ -297,9 +419,9 @@ func (s *Store) PostTransfer(
 		return uuid.Nil, fmt.Errorf("%w: currency mismatch", ErrValidation)
 	}
 
-	txID := uuid.New()
-	debitEntryID := uuid.New()
-	creditEntryID := uuid.New()
+	txID := s.newUUID()
+	debitEntryID := s.newUUID()
+	creditEntryID := s.newUUID()
 
 	// Canonical DB posting: creates ledger_tx + exactly 2 entries atomically.
 	_, err = tx.Exec(ctx, `
@@ -321,13 +443,9 @@ func (s *Store) PostTransfer(
 	}
 
 	// Build canonical minimal response once tx_id exists (stable replay contract).
-	resp := TransferResponse{TxID: txID.String()}
-	respJSON, _, err := jcsPayload(resp)
-	if err != nil {
-		return uuid.Nil, err
-	}
 	// Use JCS bytes as the stored jsonb for maximal stability.
-	respCanonBytes, err := jcs.Transform([]byte(respJSON))
+	resp := TransferResponse{TxID: txID.String()}
+	respCanonBytes, err := canonjson.Canonicalize(resp)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -344,6 +462,11 @@ func (s *Store) PostTransfer(
 	}
 	txID = committedTx
 
+	digests, err := persistAttachments(ctx, tx, s.newUUID, txID, attachments)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	// Event log append (hash-chain computed in DB trigger).
 	evPayload := transferPostedPayload{
 		TxID:        txID.String(),
@@ -353,8 +476,9 @@ func (s *Store) PostTransfer(
 		Currency:    shape.Currency,
 		ExternalRef: shape.ExternalRef,
 		Idempotency: shape.IdempotencyKey,
+		Attachments: digests,
 	}
-	if err := insertEvent(ctx, tx, "TRANSFER_POSTED", "LEDGER_TX", txID.String(), shape.CorrelationID, evPayload); err != nil {
+	if err := insertEvent(ctx, tx, s.newUUID, "TRANSFER_POSTED", "LEDGER_TX", txID.String(), shape.CorrelationID, evPayload); err != nil {
 		return uuid.Nil, err
 	}
 
@@ -0,0 +1,122 @@
+// Package canonjson is the one place core-ledger computes RFC 8785 (JSON
+// Canonicalization Scheme) bytes and domain-separated payload hashes. Every
+// writer that needs payload_canonical/payload_hash to agree byte-for-byte --
+// the event_log trigger's callers in internal/store, the risk-layer
+// ingestion tests, and any future external ingestion service -- should call
+// this package rather than re-implementing JCS locally; a second
+// hand-rolled implementation is how two writers quietly drift apart.
+package canonjson
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gowebpki/jcs"
+)
+
+// Canonicalize renders v as RFC 8785 canonical JSON: object keys sorted
+// lexicographically at every level, numbers serialized per ECMAScript
+// Number::toString, strings escaped per the JSON string grammar, encoded as
+// UTF-8. json.Marshal already rejects float64 NaN/+-Inf (v must go through
+// it first to reach jcs.Transform), so those never silently become
+// non-canonical output.
+func Canonicalize(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonjson: marshal: %w", err)
+	}
+	canon, err := jcs.Transform(raw)
+	if err != nil {
+		return nil, fmt.Errorf("canonjson: transform: %w", err)
+	}
+	return canon, nil
+}
+
+// stringifyField renders one HashPayload field the same way regardless of
+// its static type, so callers can mix strings, ints, times, and raw
+// canonical JSON in a single call without each needing its own formatting
+// convention.
+func stringifyField(f any) string {
+	switch x := f.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// HashPayload computes sha256 over domain and fields, pipe-joined in order.
+// domain must be a stable, unique prefix per hash family (e.g.
+// "valuation_snapshot:v1") -- it's what keeps a valuation hash and a
+// liquidity hash from ever colliding even if their remaining fields happen
+// to stringify the same way.
+func HashPayload(domain string, fields ...any) [32]byte {
+	parts := make([]string, 0, len(fields)+1)
+	parts = append(parts, domain)
+	for _, f := range fields {
+		parts = append(parts, stringifyField(f))
+	}
+	return sha256.Sum256([]byte(strings.Join(parts, "|")))
+}
+
+// NewValuationHash domain-separates a valuation snapshot's payload hash.
+// currency is upper-cased to match the normalization every other currency
+// field in this codebase applies before it's persisted or hashed.
+func NewValuationHash(
+	assetType, assetID string,
+	asOf time.Time,
+	price, currency, source string,
+	confidence int,
+	payloadCanonical []byte,
+) [32]byte {
+	return HashPayload(
+		"valuation_snapshot:v1",
+		assetType, assetID, asOf, price, strings.ToUpper(currency), source, confidence, payloadCanonical,
+	)
+}
+
+// NewLiquidityHash domain-separates a liquidity snapshot's payload hash.
+func NewLiquidityHash(
+	assetType, assetID string,
+	asOf time.Time,
+	haircutBps, ttcSeconds int,
+	source string,
+	payloadCanonical []byte,
+) [32]byte {
+	return HashPayload(
+		"liquidity_snapshot:v1",
+		assetType, assetID, asOf, haircutBps, ttcSeconds, source, payloadCanonical,
+	)
+}
+
+// NewEventChainPayloadHash domain-separates an event_log row's
+// payload-binding hash: sha256 over its position in the chain (seq,
+// prevHashHex), its identity (eventType/aggregateType/aggregateID/
+// correlationID), and payloadCanonical. Unlike hash_hex/prev_hash_hex,
+// which a DB trigger not defined in this tree computes, this hash is
+// computed and persisted by insertEvent itself at write time, so
+// Store.VerifyEventProof can recompute it later purely from the columns a
+// proof carries and detect a payload tampered in place without needing the
+// trigger's own byte layout.
+func NewEventChainPayloadHash(
+	seq int64,
+	eventType, aggregateType, aggregateID, correlationID string,
+	prevHashHex, payloadCanonical string,
+) [32]byte {
+	return HashPayload(
+		"event_chain_payload:v1",
+		seq, eventType, aggregateType, aggregateID, correlationID, prevHashHex, payloadCanonical,
+	)
+}
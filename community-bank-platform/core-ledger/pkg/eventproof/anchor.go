@@ -0,0 +1,57 @@
+package eventproof
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// AnchorRecord is a signed checkpoint, ready to be published somewhere an
+// operator can't quietly rewrite -- a transparency log, a public bulletin,
+// a third party's append-only store.
+type AnchorRecord struct {
+	SeqLo     uint64    `json:"seq_lo"`
+	SeqHi     uint64    `json:"seq_hi"`
+	Root      string    `json:"root"`
+	Signature string    `json:"signature,omitempty"` // hex, if signed
+	BuiltAt   time.Time `json:"built_at"`
+}
+
+// Anchor publishes a checkpoint somewhere external to the ledger DB, so a
+// post-hoc tamper (e.g. disabling triggers and rewriting event_log, as in
+// TestEventChain_TamperByDisablingTriggers_FailsVerification) can't also
+// rewrite history the operator already anchored.
+type Anchor interface {
+	Anchor(ctx context.Context, rec AnchorRecord) error
+}
+
+// FileAnchor is the default Anchor: it appends each record as a line of JSON
+// to a local file. It's intentionally the simplest thing that could anchor
+// at all -- a real deployment points Anchor at something it doesn't control
+// (e.g. a third-party transparency log), but the interface is what matters;
+// wiring a new one in shouldn't touch callers.
+type FileAnchor struct {
+	Path string
+}
+
+func (f FileAnchor) Anchor(_ context.Context, rec AnchorRecord) error {
+	if f.Path == "" {
+		return fmt.Errorf("eventproof: FileAnchor.Path is empty")
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("eventproof: marshal anchor record: %w", err)
+	}
+	b = append(b, '\n')
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("eventproof: open %s: %w", f.Path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(b)
+	return err
+}
@@ -0,0 +1,165 @@
+// Package eventproof lets a third party who does not trust the ledger
+// operator verify that a specific event_log row was included in a signed
+// Merkle checkpoint, without needing DB access. It is deliberately stateless:
+// given a Proof and a trusted root (optionally Ed25519-signed), Verify only
+// does hashing.
+package eventproof
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Sibling is one step of a Merkle authentication path.
+type Sibling struct {
+	Hash    string `json:"hash"`     // hex
+	OnRight bool   `json:"on_right"` // true if this sibling is the right child at its level
+}
+
+// Proof is everything needed to recompute a checkpoint root from a single
+// event_log row, without trusting the operator's claim about that row.
+type Proof struct {
+	Seq      uint64    `json:"seq"`
+	Leaf     string    `json:"leaf"` // hex, LeafHash(hash, prev_hash, payload_canonical) for this row
+	Siblings []Sibling `json:"siblings"`
+	SeqLo    uint64    `json:"seq_lo"`
+	SeqHi    uint64    `json:"seq_hi"`
+	Root     string    `json:"root"` // hex
+}
+
+// LeafHash is the checkpoint leaf for one event_log row: sha256 over the
+// row's own chain hash, its predecessor's chain hash, and payloadCanonical
+// itself. hashHex/prevHashHex are the same hex columns cmd/proof-verify
+// already reads off event_log_proof_export_v; payloadCanonical is the row's
+// payload_canonical. Earlier versions of this function only hashed
+// hashHex/prevHashHex -- both produced by a DB trigger whose source isn't
+// defined anywhere in this tree -- so a payload tampered in place with
+// those two columns left untouched produced an identical leaf and passed
+// checkpoint verification undetected. Folding payloadCanonical into the
+// leaf means a later tamper of that kind changes the leaf: any future
+// recomputation of checkpointLeaves against the same already-signed root
+// then fails, even though this package never touches the DB and has no way
+// to independently verify the trigger's own hash_hex byte layout.
+func LeafHash(hashHex, prevHashHex, payloadCanonical string) (string, error) {
+	h, err := hex.DecodeString(hashHex)
+	if err != nil {
+		return "", fmt.Errorf("eventproof: bad hash_hex: %w", err)
+	}
+	prev, err := hex.DecodeString(prevHashHex)
+	if err != nil {
+		return "", fmt.Errorf("eventproof: bad prev_hash_hex: %w", err)
+	}
+	payloadHash := sha256.Sum256([]byte(payloadCanonical))
+	sum := sha256.Sum256(append(append(append([]byte{}, h...), prev...), payloadHash[:]...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func pairHash(left, right string) (string, error) {
+	l, err := hex.DecodeString(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := hex.DecodeString(right)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append(append([]byte{}, l...), r...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MerkleRoot builds a binary Merkle tree over leaves (in order) and returns
+// its root. An odd level duplicates its last node, the common convention for
+// fixed-arity binary Merkle trees.
+func MerkleRoot(leaves []string) (string, error) {
+	if len(leaves) == 0 {
+		return "", fmt.Errorf("eventproof: no leaves")
+	}
+	level := append([]string(nil), leaves...)
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		next := make([]string, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			h, err := pairHash(level[i], level[i+1])
+			if err != nil {
+				return "", err
+			}
+			next = append(next, h)
+		}
+		level = next
+	}
+	return level[0], nil
+}
+
+// ProofPath returns the sibling path from leaves[index] up to the root built
+// by MerkleRoot(leaves).
+func ProofPath(leaves []string, index int) ([]Sibling, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("eventproof: index %d out of range [0,%d)", index, len(leaves))
+	}
+	level := append([]string(nil), leaves...)
+	idx := index
+	var siblings []Sibling
+
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		var sib Sibling
+		if idx%2 == 0 {
+			sib = Sibling{Hash: level[idx+1], OnRight: true}
+		} else {
+			sib = Sibling{Hash: level[idx-1], OnRight: false}
+		}
+		siblings = append(siblings, sib)
+
+		next := make([]string, 0, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			h, err := pairHash(level[i], level[i+1])
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, h)
+		}
+		level = next
+		idx /= 2
+	}
+	return siblings, nil
+}
+
+// Verify recomputes the root from p.Leaf and p.Siblings and checks it
+// matches both p.Root and the separately-trusted root the caller obtained
+// (e.g. from a signed checkpoint or an external transparency log).
+func Verify(p Proof, trustedRoot string) (bool, error) {
+	cur := p.Leaf
+	for _, s := range p.Siblings {
+		var err error
+		if s.OnRight {
+			cur, err = pairHash(cur, s.Hash)
+		} else {
+			cur, err = pairHash(s.Hash, cur)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return cur == p.Root && cur == trustedRoot, nil
+}
+
+// SignRoot signs a checkpoint root together with the window it covers, so a
+// signature can't be replayed against a different window.
+func SignRoot(priv ed25519.PrivateKey, root string, seqLo, seqHi uint64) []byte {
+	return ed25519.Sign(priv, signingMessage(root, seqLo, seqHi))
+}
+
+// VerifyRootSignature checks a signature produced by SignRoot.
+func VerifyRootSignature(pub ed25519.PublicKey, sig []byte, root string, seqLo, seqHi uint64) bool {
+	return ed25519.Verify(pub, signingMessage(root, seqLo, seqHi), sig)
+}
+
+func signingMessage(root string, seqLo, seqHi uint64) []byte {
+	return []byte(fmt.Sprintf("ledger-checkpoint:v1|%d|%d|%s", seqLo, seqHi, root))
+}
@@ -0,0 +1,166 @@
+package eventproof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+func hexHash(seed string) string {
+	h := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(h[:])
+}
+
+func TestProofPath_RoundTripsForEveryLeaf(t *testing.T) {
+	var leaves []string
+	for i := 0; i < 7; i++ { // odd count exercises the last-node duplication rule
+		h, err := LeafHash(hexHash(fmt.Sprintf("hash-%d", i)), hexHash(fmt.Sprintf("prev-%d", i)), fmt.Sprintf(`{"seq":%d}`, i))
+		if err != nil {
+			t.Fatalf("LeafHash: %v", err)
+		}
+		leaves = append(leaves, h)
+	}
+
+	root, err := MerkleRoot(leaves)
+	if err != nil {
+		t.Fatalf("MerkleRoot: %v", err)
+	}
+
+	for i := range leaves {
+		siblings, err := ProofPath(leaves, i)
+		if err != nil {
+			t.Fatalf("ProofPath(%d): %v", i, err)
+		}
+		p := Proof{Leaf: leaves[i], Siblings: siblings, Root: root}
+		ok, err := Verify(p, root)
+		if err != nil {
+			t.Fatalf("Verify(%d): %v", i, err)
+		}
+		if !ok {
+			t.Fatalf("Verify(%d): expected true", i)
+		}
+	}
+}
+
+func TestVerify_RejectsWrongRoot(t *testing.T) {
+	var leaves []string
+	for i := 0; i < 4; i++ {
+		h, _ := LeafHash(hexHash(fmt.Sprintf("h-%d", i)), hexHash(fmt.Sprintf("p-%d", i)), fmt.Sprintf(`{"seq":%d}`, i))
+		leaves = append(leaves, h)
+	}
+	root, err := MerkleRoot(leaves)
+	if err != nil {
+		t.Fatalf("MerkleRoot: %v", err)
+	}
+	siblings, err := ProofPath(leaves, 2)
+	if err != nil {
+		t.Fatalf("ProofPath: %v", err)
+	}
+	p := Proof{Leaf: leaves[2], Siblings: siblings, Root: root}
+
+	ok, err := Verify(p, hexHash("not-the-root"))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if ok {
+		t.Fatal("expected Verify to reject a mismatched trusted root")
+	}
+}
+
+func TestLeafHash_ChangesWhenPayloadCanonicalIsTamperedHashHexHeldFixed(t *testing.T) {
+	hashHex := hexHash("hash-0")
+	prevHashHex := hexHash("prev-0")
+
+	original, err := LeafHash(hashHex, prevHashHex, `{"amount_cents":100}`)
+	if err != nil {
+		t.Fatalf("LeafHash: %v", err)
+	}
+	tampered, err := LeafHash(hashHex, prevHashHex, `{"amount_cents":100000}`)
+	if err != nil {
+		t.Fatalf("LeafHash: %v", err)
+	}
+	if original == tampered {
+		t.Fatal("expected LeafHash to change when payload_canonical changes, even with hash_hex/prev_hash_hex held fixed")
+	}
+}
+
+// TestVerify_RejectsCheckpointAfterPayloadCanonicalIsTamperedTriggersDisabled
+// mirrors TestEventChain_TamperByDisablingTriggers_FailsVerification in
+// internal/store/concurrency_test.go: an admin disables triggers, updates
+// payload_json/payload_canonical in place, and re-enables triggers, leaving
+// hash_hex/prev_hash_hex untouched. A checkpoint signed before the tamper
+// must fail to re-verify once the leaf is rebuilt from the tampered
+// payload_canonical.
+func TestVerify_RejectsCheckpointAfterPayloadCanonicalIsTamperedTriggersDisabled(t *testing.T) {
+	hashHex := hexHash("hash-1")
+	prevHashHex := hexHash("prev-1")
+
+	var leaves []string
+	for i := 0; i < 4; i++ {
+		h, err := LeafHash(hexHash(fmt.Sprintf("h-%d", i)), hexHash(fmt.Sprintf("p-%d", i)), fmt.Sprintf(`{"seq":%d}`, i))
+		if err != nil {
+			t.Fatalf("LeafHash: %v", err)
+		}
+		leaves = append(leaves, h)
+	}
+	tamperedSeq := 2
+	leaf, err := LeafHash(hashHex, prevHashHex, `{"amount_cents":500}`)
+	if err != nil {
+		t.Fatalf("LeafHash: %v", err)
+	}
+	leaves[tamperedSeq] = leaf
+
+	root, err := MerkleRoot(leaves)
+	if err != nil {
+		t.Fatalf("MerkleRoot: %v", err)
+	}
+	siblings, err := ProofPath(leaves, tamperedSeq)
+	if err != nil {
+		t.Fatalf("ProofPath: %v", err)
+	}
+	p := Proof{Leaf: leaves[tamperedSeq], Siblings: siblings, Root: root}
+
+	ok, err := Verify(p, root)
+	if err != nil {
+		t.Fatalf("Verify (before tamper): %v", err)
+	}
+	if !ok {
+		t.Fatal("expected Verify to accept the untampered checkpoint")
+	}
+
+	// Triggers disabled, payload_canonical tampered in place, hash_hex/prev_hash_hex left alone.
+	tamperedLeaf, err := LeafHash(hashHex, prevHashHex, `{"amount_cents":999999}`)
+	if err != nil {
+		t.Fatalf("LeafHash (tampered): %v", err)
+	}
+	tampered := p
+	tampered.Leaf = tamperedLeaf
+
+	ok, err = Verify(tampered, root)
+	if err != nil {
+		t.Fatalf("Verify (after tamper): %v", err)
+	}
+	if ok {
+		t.Fatal("expected Verify to reject a checkpoint rebuilt from a tampered payload_canonical")
+	}
+}
+
+func TestSignRoot_VerifiesOnlyForExactWindow(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	root := hexHash("checkpoint-root")
+	sig := SignRoot(priv, root, 1, 100)
+
+	if !VerifyRootSignature(pub, sig, root, 1, 100) {
+		t.Fatal("expected signature to verify for its own window")
+	}
+	if VerifyRootSignature(pub, sig, root, 1, 101) {
+		t.Fatal("expected signature to be rejected for a different window")
+	}
+}
@@ -0,0 +1,193 @@
+// Package ledgerclient is a typed Go client generated to match
+// build/openapi/ledger.json: one method per operationId, request/response
+// bodies reusing internal/domain's types directly so the wire shape can't
+// drift from what httpapi actually serves.
+package ledgerclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/domain"
+)
+
+// Client is a small, retrying HTTP client for one core-ledger deployment.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+}
+
+// Option customizes a Client at construction time, mirroring store.Option.
+type Option func(*Client)
+
+// WithHTTPClient overrides the underlying *http.Client (timeouts, TLS, etc).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithMaxRetries overrides how many times a transient (network error or 5xx)
+// failure is retried before giving up.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithRetryWait overrides the fixed delay between retries.
+func WithRetryWait(d time.Duration) Option {
+	return func(c *Client) { c.retryWait = d }
+}
+
+// New returns a Client pointed at baseURL (e.g. "http://localhost:8080").
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: http.DefaultClient,
+		maxRetries: 2,
+		retryWait:  200 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned for any non-2xx response. Code is the discriminated
+// "code" field from the Error schema (see internal/openapi.errorSchema).
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("ledgerclient: %s (code=%s, status=%d)", e.Message, e.Code, e.StatusCode)
+}
+
+type errorEnvelope struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// do sends one request, retrying on network errors and 5xx responses up to
+// maxRetries times with a fixed wait in between -- but only when retryable
+// is true. A retry re-sends the identical marshaled body, so it's only safe
+// for a call the server can dedupe or that has no side effect to dedupe in
+// the first place: PostTransfer carries an idempotency_key the server-side
+// contract (store.PostTransfer) honors, and Balance is a pure read. Without
+// retryable, a network error or 5xx after the server already committed (a
+// lost response, not a lost request) would otherwise resend the same
+// request and -- for a call with no idempotency protection, like
+// CreateAccount -- create a second, duplicate side effect.
+func (c *Client) do(ctx context.Context, method, path, correlationID string, body, out any, retryable bool) error {
+	var raw []byte
+	if body != nil {
+		var err error
+		raw, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	maxRetries := c.maxRetries
+	if !retryable {
+		maxRetries = 0
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.retryWait):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if correlationID != "" {
+			req.Header.Set("X-Correlation-Id", correlationID)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			if out == nil {
+				return nil
+			}
+			return json.Unmarshal(respBody, out)
+		}
+
+		var env errorEnvelope
+		_ = json.Unmarshal(respBody, &env)
+		apiErr := &APIError{StatusCode: resp.StatusCode, Code: env.Code, Message: env.Error}
+
+		if resp.StatusCode < 500 {
+			return apiErr // client error: retrying would just repeat it
+		}
+		lastErr = apiErr
+	}
+	return lastErr
+}
+
+// CreateAccount calls POST /v1/accounts. Unlike PostTransfer, account
+// creation carries no idempotency key at the domain or store level (see
+// domain.CreateAccountRequest, store.CreateAccount), so a retried call after
+// a lost response -- the server committed but the client never saw the
+// 2xx -- would create a second, duplicate account. This call is therefore
+// never retried: a network error or 5xx surfaces directly to the caller,
+// who is in the best position to decide whether it's safe to try again.
+func (c *Client) CreateAccount(ctx context.Context, label, currency, correlationID string) (uuid.UUID, error) {
+	req := domain.CreateAccountRequest{Label: label, Currency: currency}
+	var resp domain.CreateAccountResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/accounts", correlationID, req, &resp, false); err != nil {
+		return uuid.Nil, err
+	}
+	return resp.AccountID, nil
+}
+
+// PostTransfer calls POST /v1/transfers. req.IdempotencyKey and
+// req.CorrelationID are forwarded as-is; req.CorrelationID also becomes the
+// X-Correlation-Id header, matching httpapi.PostTransfer's header/body
+// precedence. Retried on transient failure: req.IdempotencyKey is identical
+// across attempts, and store.PostTransfer's idempotency contract is what
+// makes replaying the same request safe.
+func (c *Client) PostTransfer(ctx context.Context, req domain.PostTransferRequest) (uuid.UUID, error) {
+	var resp domain.PostTransferResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/transfers", req.CorrelationID, req, &resp, true); err != nil {
+		return uuid.Nil, err
+	}
+	return resp.TxID, nil
+}
+
+// Balance calls GET /v1/accounts/{account_id}/balance. Retried on transient
+// failure: a read has no side effect to duplicate.
+func (c *Client) Balance(ctx context.Context, accountID uuid.UUID, correlationID string) (currency string, balanceCents int64, err error) {
+	var resp domain.BalanceResponse
+	path := fmt.Sprintf("/v1/accounts/%s/balance", accountID)
+	if err := c.do(ctx, http.MethodGet, path, correlationID, nil, &resp, true); err != nil {
+		return "", 0, err
+	}
+	return resp.Currency, resp.BalanceCents, nil
+}
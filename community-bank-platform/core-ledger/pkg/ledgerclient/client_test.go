@@ -0,0 +1,105 @@
+package ledgerclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"core-ledger/internal/domain"
+	"core-ledger/internal/httpapi"
+	"core-ledger/internal/store/memstore"
+	"core-ledger/pkg/ledgerclient"
+)
+
+// TestClient_EndToEndAgainstMemstore drives the generated client through a
+// full create-account/post-transfer/balance round trip against the
+// in-memory backend, with no Postgres required -- the smoke test the spec
+// generation work was meant to be checkable with.
+func TestClient_EndToEndAgainstMemstore(t *testing.T) {
+	h := httpapi.NewHandlers(memstore.New())
+	srv := httptest.NewServer(httpapi.Router(h))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := ledgerclient.New(srv.URL)
+
+	alice, err := c.CreateAccount(ctx, "alice", "USD", "smoke-1")
+	if err != nil {
+		t.Fatalf("CreateAccount(alice): %v", err)
+	}
+	bob, err := c.CreateAccount(ctx, "bob", "USD", "smoke-2")
+	if err != nil {
+		t.Fatalf("CreateAccount(bob): %v", err)
+	}
+
+	if _, err := c.PostTransfer(ctx, domain.PostTransferRequest{
+		FromAccountID:  alice,
+		ToAccountID:    bob,
+		AmountCents:    500,
+		Currency:       "USD",
+		ExternalRef:    "smoke-ext-1",
+		IdempotencyKey: "smoke-idem-1",
+		CorrelationID:  "smoke-3",
+	}); err != nil {
+		t.Fatalf("PostTransfer: %v", err)
+	}
+
+	cur, bal, err := c.Balance(ctx, bob, "smoke-4")
+	if err != nil {
+		t.Fatalf("Balance(bob): %v", err)
+	}
+	if cur != "USD" || bal != 500 {
+		t.Fatalf("Balance(bob) = %s %d, want USD 500", cur, bal)
+	}
+}
+
+// TestClient_CreateAccountIsNotRetried guards against the duplicate-account
+// risk a retry would reintroduce: CreateAccount has no idempotency
+// protection at the domain or store level, so a 5xx must surface to the
+// caller on the first failure rather than being retried against a server
+// that may have already committed the account.
+func TestClient_CreateAccountIsNotRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := ledgerclient.New(srv.URL, ledgerclient.WithMaxRetries(2))
+
+	if _, err := c.CreateAccount(context.Background(), "alice", "USD", "retry-test"); err == nil {
+		t.Fatal("expected CreateAccount to surface the server error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected CreateAccount to be attempted exactly once, got %d calls", got)
+	}
+}
+
+// TestClient_BalanceIsRetried confirms a read-only call still benefits from
+// the retry loop CreateAccount is deliberately excluded from.
+func TestClient_BalanceIsRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"currency":"USD","balance_cents":0}`))
+	}))
+	defer srv.Close()
+
+	c := ledgerclient.New(srv.URL, ledgerclient.WithMaxRetries(2), ledgerclient.WithRetryWait(0))
+
+	if _, _, err := c.Balance(context.Background(), uuid.New(), "retry-test"); err != nil {
+		t.Fatalf("expected Balance to succeed after one retry: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected Balance to be attempted twice, got %d calls", got)
+	}
+}